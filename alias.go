@@ -7,6 +7,12 @@ type Alias struct {
 	string
 }
 
+// isExpression returns true if the string contains
+// parameter reference "$(...)" or expression "${...}".
+func isExpression(s string) bool {
+	return strings.Contains(s, "$(") || strings.Contains(s, "${")
+}
+
 // Key extract the exact (and flattened) name of an expression.
 func (a *Alias) Key() string {
 	return strings.Trim(a.string, "$()")
@@ -4,7 +4,7 @@ package cwl
 // @see http://www.commonwl.org/v1.0/CommandLineTool.html#CommandLineTool
 type Argument struct {
 	Value   string
-	Binding *Binding
+	Binding *CommandLineBinding
 }
 
 // New constructs an "Argument" struct from any interface.
@@ -13,8 +13,10 @@ func (_ Argument) New(i interface{}) Argument {
 	switch x := i.(type) {
 	case string:
 		dest.Value = x
+	case *Object:
+		dest.Binding = CommandLineBinding{}.New(x)
 	case map[string]interface{}:
-		dest.Binding = Binding{}.New(x)
+		return Argument{}.New(NewObject(x))
 	}
 	return dest
 }
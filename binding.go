@@ -1,50 +1,131 @@
 package cwl
 
-// Binding represents and combines "CommandLineBinding" and "CommandOutputBinding"
-// @see
-// - http://www.commonwl.org/v1.0/CommandLineTool.html#CommandLineBinding
-// - http://www.commonwl.org/v1.0/CommandLineTool.html#CommandOutputBinding
-type Binding struct {
-	// Common
-	LoadContents bool
-	// CommandLineBinding
-	Position   int    `json:"position"`
-	Prefix     string `json:"prefix"`
-	Separate   bool   `json:"separate"`
-	Separator  string `json:"separator"`
+import "fmt"
+
+// CommandLineBinding represents "inputBinding" of inputs, input schemas and "arguments".
+// @see http://www.commonwl.org/v1.0/CommandLineTool.html#CommandLineBinding
+type CommandLineBinding struct {
+	LoadContents bool `json:"loadContents"`
+	// Position is 0 by default
+	Position int `json:"position"`
+	// PositionExpression only appears if "position" is an expression, since v1.1
+	PositionExpression string
+	Prefix             string `json:"prefix"`
+	// Separate is true by default
+	Separate  bool   `json:"separate"`
+	Separator string `json:"itemSeparator"`
+	// ShellQuote is true by default
 	ShellQuote bool   `json:"shellQuote"`
 	ValueFrom  *Alias `json:"valueFrom"`
-	// CommandOutputBinding
-	Glob     []string `json:"glob"`
-	Eval     string   `json:"outputEval"`
-	Contents bool     `json:"loadContents"`
 }
 
-// New constructs new "Binding".
-func (binding Binding) New(i interface{}) *Binding {
-	dest := new(Binding)
+// New constructs new "CommandLineBinding".
+func (_ CommandLineBinding) New(i interface{}) *CommandLineBinding {
+	dest := &CommandLineBinding{Separate: true, ShellQuote: true}
 	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		x.declare("CommandLineBinding")
+		for _, key := range x.Keys {
 			switch key {
 			case "position":
-				dest.Position = int(v.(float64))
+				dest.Position, dest.PositionExpression = x.IntOrExpression(key)
 			case "prefix":
-				dest.Prefix = v.(string)
+				dest.Prefix = x.String(key)
+			case "separate":
+				dest.Separate = x.Bool(key)
 			case "itemSeparator":
-				dest.Separator = v.(string)
+				dest.Separator = x.String(key)
 			case "loadContents":
-				dest.LoadContents = v.(bool)
-			case "glob":
-				dest.Glob = StringArrayable(v)
+				dest.LoadContents = x.Bool(key)
 			case "shellQuote":
-				dest.ShellQuote = v.(bool)
+				dest.ShellQuote = x.Bool(key)
 			case "valueFrom":
-				dest.ValueFrom = &Alias{v.(string)}
+				dest.ValueFrom = &Alias{x.String(key)}
+			}
+		}
+	case map[string]interface{}:
+		return CommandLineBinding{}.New(NewObject(x))
+	}
+	return dest
+}
+
+// Expressions returns the expressions in this binding.
+func (binding *CommandLineBinding) Expressions() []string {
+	dest := []string{}
+	if binding.PositionExpression != "" {
+		dest = append(dest, binding.PositionExpression)
+	}
+	if binding.ValueFrom != nil && isExpression(binding.ValueFrom.string) {
+		dest = append(dest, binding.ValueFrom.string)
+	}
+	return dest
+}
+
+// CommandOutputBinding represents "outputBinding" of outputs and output schemas.
+// @see http://www.commonwl.org/v1.0/CommandLineTool.html#CommandOutputBinding
+type CommandOutputBinding struct {
+	// Glob is the list of patterns, each of which can be an expression
+	Glob         []string `json:"glob"`
+	LoadContents bool     `json:"loadContents"`
+	// LoadListing appears since v1.1
+	LoadListing string `json:"loadListing"`
+	// Eval is the expression of "outputEval"
+	Eval string `json:"outputEval"`
+}
+
+// New constructs new "CommandOutputBinding".
+func (_ CommandOutputBinding) New(i interface{}) *CommandOutputBinding {
+	dest := new(CommandOutputBinding)
+	switch x := i.(type) {
+	case *Object:
+		x.declare("CommandOutputBinding")
+		for _, key := range x.Keys {
+			switch key {
+			case "glob":
+				dest.Glob = x.Strings(key)
+			case "loadContents":
+				dest.LoadContents = x.Bool(key)
+			case "loadListing":
+				dest.LoadListing = x.Enum(key, loadListings...)
 			case "outputEval":
-				dest.Eval = v.(string)
+				dest.Eval = x.String(key)
+				if s, ok := x.Values[key].(string); ok && !isExpression(s) {
+					x.failKey(key, "\"outputEval\" must be an expression")
+				}
 			}
 		}
+	case map[string]interface{}:
+		return CommandOutputBinding{}.New(NewObject(x))
+	}
+	return dest
+}
+
+// Expressions returns the expressions in this binding.
+func (binding *CommandOutputBinding) Expressions() []string {
+	dest := []string{}
+	for _, glob := range binding.Glob {
+		if isExpression(glob) {
+			dest = append(dest, glob)
+		}
+	}
+	if binding.Eval != "" {
+		dest = append(dest, binding.Eval)
 	}
 	return dest
 }
+
+// LoadContentsLimit is the maximum size of the file for "loadContents".
+const LoadContentsLimit = 64 * 1024
+
+// LoadContents returns "contents" of the file for "loadContents" by the semantics of the version:
+// until v1.1, it's truncated to the first LoadContentsLimit bytes,
+// and since v1.2, it's an error if the file is larger than that.
+func LoadContents(version string, content []byte) (string, error) {
+	if len(content) <= LoadContentsLimit {
+		return string(content), nil
+	}
+	if atLeast(version, Version12) {
+		return "", fmt.Errorf("file size %d exceeds %d bytes of loadContents", len(content), LoadContentsLimit)
+	}
+	return string(content[:LoadContentsLimit]), nil
+}
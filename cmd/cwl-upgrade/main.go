@@ -0,0 +1,51 @@
+// cwl-upgrade upgrades CWL documents of draft-3, v1.0 and v1.1 to newer cwlVersion.
+//
+//	cwl-upgrade [-v v1.2] [-w] file.cwl...
+//
+// It prints the upgraded YAML, or overwrites the files with -w.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	cwl "github.com/otiai10/cwl.go"
+)
+
+func main() {
+	version := flag.String("v", cwl.Version12, "cwlVersion to upgrade to")
+	write := flag.Bool("w", false, "write result to the source file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-v version] [-w] file.cwl...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for _, path := range flag.Args() {
+		if err := upgrade(path, *version, *write); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func upgrade(path, version string, write bool) error {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	upgraded, err := cwl.UpgradeYAML(src, version)
+	if err != nil {
+		return err
+	}
+	if write {
+		return ioutil.WriteFile(path, upgraded, 0644)
+	}
+	_, err = os.Stdout.Write(upgraded)
+	return err
+}
@@ -0,0 +1,99 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pickValues are symbols of PickValueMethod.
+// @see https://www.commonwl.org/v1.2/Workflow.html#PickValueMethod
+var pickValues = []string{"first_non_null", "the_only_non_null", "all_non_null"}
+
+// Conditional returns true if the step has "when" condition,
+// so that its outputs are null if the condition is false.
+func (step Step) Conditional() bool {
+	return step.When != ""
+}
+
+// checkConditionals records ParseErrors for the outputs of this workflow
+// which take values from conditional steps, as v1.2 requires:
+// they must accept null, unless "pickValue" resolves the null,
+// and "all_non_null" must be an array.
+func (root *Root) checkConditionals(docs *Object) {
+	conditional := map[string]bool{}
+	for _, step := range root.Steps {
+		if step.Conditional() {
+			conditional[ShortName(step.ID)] = true
+		}
+	}
+	for _, output := range root.Outputs {
+		if output.PickValue == "all_non_null" && !isArray(output.Types) {
+			failOutput(docs, output.ID, "pickValue", "output with pickValue \"all_non_null\" must be an array")
+		}
+		if output.PickValue != "" || isNullable(output.Types) {
+			continue
+		}
+		for _, src := range output.Source {
+			if step := sourceStep(src); conditional[step] {
+				failOutput(docs, output.ID, "outputSource", fmt.Sprintf("output from conditional step \"%s\" must be nullable or use pickValue", step))
+			}
+		}
+	}
+}
+
+// sourceStep returns the short name of the step the source refers to, e.g. "step1" for "#main/step1/out".
+func sourceStep(src string) string {
+	_, fragment := splitFragment(src)
+	if fragment == "" {
+		fragment = src
+	}
+	segments := strings.Split(fragment, "/")
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[len(segments)-2]
+}
+
+// isNullable returns true if the types accept null.
+func isNullable(types []Type) bool {
+	for _, t := range types {
+		if t.Type == "null" || strings.HasSuffix(t.Type, "?") {
+			return true
+		}
+	}
+	return false
+}
+
+// isArray returns true if the types are array, allowing null.
+func isArray(types []Type) bool {
+	found := false
+	for _, t := range types {
+		switch {
+		case t.Type == "null":
+		case t.Type == "array", strings.HasSuffix(strings.TrimSuffix(t.Type, "?"), "[]"):
+			found = true
+		default:
+			return false
+		}
+	}
+	return found
+}
+
+// failOutput records a ParseError for the key of the output identified by the ID,
+// in either map-form or list-form "outputs".
+func failOutput(docs *Object, id, key, message string) {
+	switch outputs := docs.Values["outputs"].(type) {
+	case *Object:
+		if obj, ok := outputs.Values[id].(*Object); ok {
+			obj.failKey(key, message)
+		} else {
+			outputs.failKey(id, message)
+		}
+	case []interface{}:
+		for _, v := range outputs {
+			if obj, ok := v.(*Object); ok && obj.Values["id"] == id {
+				obj.failKey(key, message)
+			}
+		}
+	}
+}
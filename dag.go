@@ -0,0 +1,292 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of Node.
+const (
+	NodeInput  = "input"
+	NodeStep   = "step"
+	NodeOutput = "output"
+)
+
+// Node represents an input, a step or an output of a workflow in DAG.
+type Node struct {
+	// ID is the short name of the parameter or the step,
+	// prefixed by the path of the nested workflow such as "outer/inner/step1"
+	ID string
+	// Kind is one of NodeInput, NodeStep and NodeOutput
+	Kind string
+	// Workflow is the path of the nested workflow which contains this node, empty for the root
+	Workflow string
+	// Step only appears if Kind is NodeStep
+	Step *Step
+	// Input only appears if Kind is NodeInput, and Output only appears if Kind is NodeOutput
+	Input  *Input
+	Output *Output
+}
+
+// Name returns the short name of the node without the path of the workflow.
+func (node *Node) Name() string {
+	return node.ID[strings.LastIndex(node.ID, "/")+1:]
+}
+
+// Edge represents a data link from a source to a sink in DAG.
+type Edge struct {
+	From string
+	// FromPort is the name of the step output, empty if the source is a workflow input
+	FromPort string
+	To       string
+	// ToPort is the name of the step input, empty if the sink is a workflow output
+	ToPort string
+}
+
+// DAG represents the dependencies of a workflow as a directed acyclic graph,
+// whose nodes are inputs, steps and outputs of the workflow.
+// Steps which run nested workflows are expanded into the nodes of the nested workflows,
+// so that the edges go into the inputs and come out of the outputs of the nested workflows.
+type DAG struct {
+	Nodes []*Node
+	Edges []Edge
+	// Root is the workflow of this graph
+	Root *Root
+	// Workflows are the steps which run nested workflows, keyed by the paths of the nested workflows
+	Workflows map[string]*Step
+}
+
+// NewDAG constructs DAG of the workflow, or of the entry point of "$graph".
+// Since NewDAG resolves the entry point by Entry(""), a root of "$graph"
+// should be passed as it is, not the entry already resolved from it.
+func NewDAG(root *Root) (*DAG, error) {
+	entry, err := root.Entry("")
+	if err != nil {
+		return nil, err
+	}
+	if entry.Class != "Workflow" {
+		return nil, fmt.Errorf("graph can't be constructed from %s", entry.Class)
+	}
+	g := &DAG{Nodes: []*Node{}, Edges: []Edge{}, Root: entry, Workflows: map[string]*Step{}}
+	g.add(entry, "", nil)
+	return g, nil
+}
+
+// add adds the nodes and the edges of the workflow nested in the path.
+func (g *DAG) add(root *Root, path string, stack []*Root) {
+	prefix := ""
+	if path != "" {
+		prefix = path + "/"
+	}
+	for i := range root.Inputs {
+		g.Nodes = append(g.Nodes, &Node{ID: prefix + ShortName(root.Inputs[i].ID), Kind: NodeInput, Workflow: path, Input: &root.Inputs[i]})
+	}
+	nested := map[string]bool{}
+	for i := range root.Steps {
+		step := &root.Steps[i]
+		name := ShortName(step.ID)
+		if run := step.Run.Workflow; run != nil && run.Class == "Workflow" && !containsRoot(stack, run) {
+			nested[name] = true
+			g.Workflows[prefix+name] = step
+			g.add(run, prefix+name, append(stack, root))
+			continue
+		}
+		g.Nodes = append(g.Nodes, &Node{ID: prefix + name, Kind: NodeStep, Workflow: path, Step: step})
+	}
+	for i := range root.Outputs {
+		g.Nodes = append(g.Nodes, &Node{ID: prefix + ShortName(root.Outputs[i].ID), Kind: NodeOutput, Workflow: path, Output: &root.Outputs[i]})
+	}
+	// source returns the node and the port of the source.
+	source := func(src string) (string, string) {
+		port := ShortName(src)
+		if step := sourceStep(src); step != "" && root.hasStep(step) {
+			if nested[step] {
+				return prefix + step + "/" + port, ""
+			}
+			return prefix + step, port
+		}
+		return prefix + port, ""
+	}
+	for _, step := range root.Steps {
+		name := ShortName(step.ID)
+		for _, in := range step.In {
+			to, port := prefix+name, ShortName(in.ID)
+			if nested[name] {
+				to, port = prefix+name+"/"+port, ""
+			}
+			for _, src := range in.Source {
+				from, fromPort := source(src)
+				g.Edges = append(g.Edges, Edge{From: from, FromPort: fromPort, To: to, ToPort: port})
+			}
+		}
+	}
+	for _, output := range root.Outputs {
+		for _, src := range output.Source {
+			from, fromPort := source(src)
+			g.Edges = append(g.Edges, Edge{From: from, FromPort: fromPort, To: prefix + ShortName(output.ID)})
+		}
+	}
+}
+
+// hasStep returns true if the workflow has the step of the short name.
+func (root *Root) hasStep(name string) bool {
+	for _, step := range root.Steps {
+		if ShortName(step.ID) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRoot returns true if the stack contains the process.
+func containsRoot(stack []*Root, root *Root) bool {
+	for _, p := range stack {
+		if p == root {
+			return true
+		}
+	}
+	return false
+}
+
+// Node returns the node of the ID, or nil if it's not found.
+func (g *DAG) Node(id string) *Node {
+	for _, node := range g.Nodes {
+		if node.ID == id {
+			return node
+		}
+	}
+	return nil
+}
+
+// Steps returns the step nodes in the order of the document.
+func (g *DAG) Steps() []*Node {
+	dest := []*Node{}
+	for _, node := range g.Nodes {
+		if node.Kind == NodeStep {
+			dest = append(dest, node)
+		}
+	}
+	return dest
+}
+
+// Upstream returns the steps which the step directly depends on,
+// looking through the inputs and outputs of nested workflows.
+func (g *DAG) Upstream(step string) []*Node {
+	return g.neighbors(step, func(e Edge) (string, string) { return e.To, e.From })
+}
+
+// Downstream returns the steps which directly depend on the step,
+// looking through the inputs and outputs of nested workflows.
+func (g *DAG) Downstream(step string) []*Node {
+	return g.neighbors(step, func(e Edge) (string, string) { return e.From, e.To })
+}
+
+// neighbors returns the nearest steps following the edges in the direction,
+// which returns the node to follow from and the node to go to.
+func (g *DAG) neighbors(id string, direction func(Edge) (string, string)) []*Node {
+	dest := []*Node{}
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) != 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, e := range g.Edges {
+			from, to := direction(e)
+			if from != current || visited[to] {
+				continue
+			}
+			visited[to] = true
+			node := g.Node(to)
+			if node == nil {
+				continue
+			}
+			if node.Kind == NodeStep {
+				dest = append(dest, node)
+			} else {
+				queue = append(queue, to)
+			}
+		}
+	}
+	return dest
+}
+
+// Roots returns the steps which depend on no other step.
+func (g *DAG) Roots() []*Node {
+	dest := []*Node{}
+	for _, node := range g.Steps() {
+		if len(g.Upstream(node.ID)) == 0 {
+			dest = append(dest, node)
+		}
+	}
+	return dest
+}
+
+// Leaves returns the steps which no other step depends on.
+func (g *DAG) Leaves() []*Node {
+	dest := []*Node{}
+	for _, node := range g.Steps() {
+		if len(g.Downstream(node.ID)) == 0 {
+			dest = append(dest, node)
+		}
+	}
+	return dest
+}
+
+// TopologicalOrder returns the steps in the order to run,
+// in which every step comes after the steps it depends on,
+// i.e. the steps of Levels from the first level, in the order of the document in each level.
+// It returns an error if the steps form a cycle.
+func (g *DAG) TopologicalOrder() ([]*Node, error) {
+	levels, err := g.Levels()
+	if err != nil {
+		return nil, err
+	}
+	dest := []*Node{}
+	for _, level := range levels {
+		dest = append(dest, level...)
+	}
+	return dest, nil
+}
+
+// Levels returns the steps grouped by the depth of their dependencies,
+// so that the steps in the same level can run in parallel after the previous levels.
+// It returns an error if the steps form a cycle.
+func (g *DAG) Levels() ([][]*Node, error) {
+	steps := g.Steps()
+	depth := map[string]int{}
+	upstream := map[string][]*Node{}
+	for _, node := range steps {
+		upstream[node.ID] = g.Upstream(node.ID)
+	}
+	levels := [][]*Node{}
+	for len(depth) < len(steps) {
+		level := []*Node{}
+		for _, node := range steps {
+			if _, done := depth[node.ID]; done {
+				continue
+			}
+			ready := true
+			for _, up := range upstream[node.ID] {
+				if d, done := depth[up.ID]; !done || d == len(levels) {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				level = append(level, node)
+				depth[node.ID] = len(levels)
+			}
+		}
+		if len(level) == 0 {
+			remaining := []string{}
+			for _, node := range steps {
+				if _, done := depth[node.ID]; !done {
+					remaining = append(remaining, node.ID)
+				}
+			}
+			return nil, fmt.Errorf("steps form a cycle: %s", strings.Join(remaining, ", "))
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
@@ -0,0 +1,144 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Levels where an effective requirement can come from, from the outermost.
+const (
+	LevelWorkflow = "workflow"
+	LevelStep     = "step"
+	LevelProcess  = "process"
+)
+
+// EffectiveRequirement is a requirement which applies to a step,
+// with where it comes from.
+// It's Soft if it comes from "hints".
+type EffectiveRequirement struct {
+	Requirement
+	// Level is one of LevelWorkflow, LevelStep and LevelProcess
+	Level string
+	// Source is the ID of the workflow, step or process which gives this requirement
+	Source string
+}
+
+// EffectiveRequirements returns the requirements and hints which apply to the step of the workflow,
+// merged across the workflow, the step and the process to run.
+// The most specific requirement of a class takes precedence, i.e. the process over the step,
+// and the step over the workflow, and requirements take precedence over hints at any level.
+// The step is specified by its ID, or by the path of short names such as "outer/inner"
+// for steps of nested workflows, whose "run" must be already loaded.
+// @see http://www.commonwl.org/v1.0/Workflow.html#Requirements_and_hints
+func EffectiveRequirements(workflow *Root, stepID string) ([]EffectiveRequirement, error) {
+	chain := stepChain(workflow, stepID)
+	if chain == nil {
+		return nil, fmt.Errorf("step not found: %s", stepID)
+	}
+	levels := []requirementLevel{{
+		name:         LevelWorkflow,
+		source:       processSource(workflow, ""),
+		requirements: workflow.Requirements,
+		hints:        workflow.Hints,
+	}}
+	for _, step := range chain {
+		levels = append(levels, requirementLevel{
+			name:         LevelStep,
+			source:       step.ID,
+			requirements: step.Requirements,
+			hints:        step.Hints,
+		})
+		run := step.Run.Workflow
+		if run == nil {
+			return nil, fmt.Errorf("run of step %s is not loaded", step.ID)
+		}
+		levels = append(levels, requirementLevel{
+			name:         LevelProcess,
+			source:       processSource(run, step.ID+"/run"),
+			requirements: run.Requirements,
+			hints:        run.Hints,
+		})
+	}
+	return mergeRequirements(levels), nil
+}
+
+// requirementLevel is a set of requirements and hints given at a level.
+type requirementLevel struct {
+	name         string
+	source       string
+	requirements Requirements
+	hints        Hints
+}
+
+// mergeRequirements merges requirements of the levels from the outermost,
+// replacing them by class.
+func mergeRequirements(levels []requirementLevel) []EffectiveRequirement {
+	requirements := map[string]EffectiveRequirement{}
+	hints := map[string]EffectiveRequirement{}
+	classes := []string{}
+	for _, level := range levels {
+		for _, r := range level.requirements {
+			if _, ok := requirements[r.Class]; !ok {
+				classes = append(classes, r.Class)
+			}
+			requirements[r.Class] = EffectiveRequirement{Requirement: r, Level: level.name, Source: level.source}
+		}
+	}
+	hinted := []string{}
+	for _, level := range levels {
+		for _, h := range level.hints {
+			if _, ok := requirements[h.Class]; ok {
+				continue
+			}
+			if _, ok := hints[h.Class]; !ok {
+				hinted = append(hinted, h.Class)
+			}
+			hints[h.Class] = EffectiveRequirement{Requirement: h.Requirement, Level: level.name, Source: level.source}
+		}
+	}
+	dest := []EffectiveRequirement{}
+	for _, class := range classes {
+		dest = append(dest, requirements[class])
+	}
+	for _, class := range hinted {
+		dest = append(dest, hints[class])
+	}
+	return dest
+}
+
+// stepChain finds the step of the ID, and returns the steps from the outermost to it.
+// Full IDs are searched in nested workflows as well,
+// while short names must be given as the path through the nested workflows.
+func stepChain(root *Root, id string) []*Step {
+	for i := range root.Steps {
+		step := &root.Steps[i]
+		name := ShortName(step.ID)
+		if step.ID == id || name == id {
+			return []*Step{step}
+		}
+		if step.Run.Workflow == nil {
+			continue
+		}
+		inner := id
+		if strings.HasPrefix(id, name+"/") {
+			inner = strings.TrimPrefix(id, name+"/")
+		} else if !strings.Contains(id, "#") {
+			continue
+		}
+		if chain := stepChain(step.Run.Workflow, inner); chain != nil {
+			return append([]*Step{step}, chain...)
+		}
+	}
+	return nil
+}
+
+// processSource returns the ID of the process, the path of the document, or the fallback.
+func processSource(root *Root, fallback string) string {
+	if root.ID != "" {
+		return root.ID
+	}
+	if root.Path != "" {
+		return root.Path
+	}
+	return fallback
+}
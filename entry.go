@@ -1,6 +1,7 @@
 package cwl
 
-// Entry represents fs entry, it means [File|Directory|Dirent]
+// Entry represents fs entry, it means [File|Directory|Dirent],
+// or an expression which is evaluated to them.
 type Entry struct {
 	Class    string
 	Location string
@@ -9,14 +10,17 @@ type Entry struct {
 	File
 	Directory
 	Dirent
+	// Expression only appears if the entry is an expression
+	Expression string
 }
 
 // File represents file entry.
 // @see http://www.commonwl.org/v1.0/CommandLineTool.html#File
 type File struct {
-	Dirname string
-	Size    int64
-	Format  string
+	Dirname  string
+	Size     int64
+	Format   string
+	Contents string
 }
 
 // Directory represents direcotry entry.
@@ -39,10 +43,14 @@ func (_ Entry) NewList(i interface{}) []Entry {
 	switch x := i.(type) {
 	case string:
 		dest = append(dest, Entry{}.New(x))
+	case *Object:
+		dest = append(dest, Entry{}.New(x))
 	case []interface{}:
 		for _, v := range x {
 			dest = append(dest, Entry{}.New(v))
 		}
+	case map[string]interface{}:
+		return Entry{}.NewList(NewObject(x))
 	}
 	return dest
 }
@@ -52,18 +60,47 @@ func (_ Entry) New(i interface{}) Entry {
 	dest := Entry{}
 	switch x := i.(type) {
 	case string:
-		dest.Location = x
-	case map[string]interface{}:
-		for key, v := range x {
+		if isExpression(x) {
+			dest.Expression = x
+		} else {
+			dest.Location = x
+		}
+	case *Object:
+		for _, key := range x.Keys {
 			switch key {
+			case "class":
+				dest.Class = x.String(key)
+			case "location":
+				dest.Location = x.String(key)
+			case "path":
+				dest.Path = x.String(key)
+			case "basename":
+				dest.Basename = x.String(key)
+			case "format":
+				dest.Format = x.String(key)
+			case "dirname":
+				dest.Dirname = x.String(key)
+			case "size":
+				dest.Size = int64(x.Int(key))
+			case "contents":
+				dest.Contents = x.String(key)
+			case "listing":
+				dest.Listing = Entry{}.NewList(x.Values[key])
 			case "entryname":
-				dest.EntryName = v.(string)
+				dest.EntryName = x.String(key)
 			case "entry":
-				dest.Entry = v.(string)
+				dest.Entry = x.String(key)
 			case "writable":
-				dest.Writable = v.(bool)
+				dest.Writable = x.Bool(key)
 			}
 		}
+		if dest.Class == "" {
+			x.declare("Dirent")
+		} else {
+			x.declare(dest.Class)
+		}
+	case map[string]interface{}:
+		return Entry{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -8,13 +8,39 @@ type EnvDef struct {
 	Value string
 }
 
-// NewList constructs a list of EnvDef from interface
+// NewList constructs a list of EnvDef from list-form or map-form "envDef".
 func (_ EnvDef) NewList(i interface{}) []EnvDef {
 	dest := []EnvDef{}
 	switch x := i.(type) {
+	case []interface{}:
+		for _, v := range x {
+			switch e := v.(type) {
+			case *Object:
+				dest = append(dest, EnvDef{}.New(e))
+			case map[string]interface{}:
+				dest = append(dest, EnvDef{}.New(NewObject(e)))
+			}
+		}
+	case *Object:
+		for _, key := range x.Keys {
+			dest = append(dest, EnvDef{Name: key, Value: x.String(key)})
+		}
 	case map[string]interface{}:
-		for key, v := range x {
-			dest = append(dest, EnvDef{Name: key, Value: v.(string)})
+		return EnvDef{}.NewList(NewObject(x))
+	}
+	return dest
+}
+
+// New constructs an EnvDef from an object of "envName" and "envValue".
+func (_ EnvDef) New(x *Object) EnvDef {
+	x.declare("EnvironmentDef")
+	dest := EnvDef{}
+	for _, key := range x.Keys {
+		switch key {
+		case "envName":
+			dest.Name = x.String(key)
+		case "envValue":
+			dest.Value = x.String(key)
 		}
 	}
 	return dest
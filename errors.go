@@ -0,0 +1,67 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError represents a value which doesn't have the expected kind,
+// located by its path in the document and its position in the source.
+type ParseError struct {
+	Path     string // e.g. "steps.step1.in.echo_in1.valueFrom"
+	Expected string // e.g. "string"
+	Actual   string // e.g. "mapping"
+	Line     int    // 1-origin, 0 if unknown
+	Column   int    // 1-origin, 0 if unknown
+	// Message describes the error, only if it's not about the kind of the value.
+	Message string
+}
+
+// Error implements error interface.
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("%s: expected %s but got %s", e.Path, e.Expected, e.Actual)
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", e.Path, e.Message)
+		if e.Path == "" {
+			msg = e.Message
+		}
+	}
+	if e.Line == 0 {
+		return "Parse error: " + msg
+	}
+	return fmt.Sprintf("Parse error at line %d, column %d: %s", e.Line, e.Column, msg)
+}
+
+// ParseErrors represents all the ParseError found in a document.
+type ParseErrors []*ParseError
+
+// Error implements error interface.
+func (errs ParseErrors) Error() string {
+	lines := []string{}
+	for _, e := range errs {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// kindOf returns YAML kind name of a decoded value.
+func kindOf(i interface{}) string {
+	switch x := i.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if x == float64(int(x)) {
+			return "int"
+		}
+		return "number"
+	case *Object, map[string]interface{}:
+		return "mapping"
+	case []interface{}:
+		return "sequence"
+	}
+	return fmt.Sprintf("%T", i)
+}
@@ -0,0 +1,39 @@
+package cwl
+
+// Status represents the status of a finished process.
+type Status string
+
+// Statuses of a finished process, classified by the exit code.
+const (
+	StatusSuccess       Status = "success"
+	StatusTemporaryFail Status = "temporaryFail"
+	StatusPermanentFail Status = "permanentFail"
+)
+
+// ClassifyExit returns the status of this CommandLineTool which exited with the code.
+// "successCodes", "temporaryFailCodes" and "permanentFailCodes" are looked up in this order,
+// and by default, 0 is success and any other code is permanentFail.
+// @see http://www.commonwl.org/v1.0/CommandLineTool.html#CommandLineTool
+func (root *Root) ClassifyExit(code int) Status {
+	switch {
+	case containsCode(root.SuccessCodes, code):
+		return StatusSuccess
+	case containsCode(root.TemporaryFailCodes, code):
+		return StatusTemporaryFail
+	case containsCode(root.PermanentFailCodes, code):
+		return StatusPermanentFail
+	case code == 0:
+		return StatusSuccess
+	}
+	return StatusPermanentFail
+}
+
+// containsCode returns true if the codes contain the code.
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
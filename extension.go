@@ -0,0 +1,119 @@
+package cwl
+
+import "sync"
+
+// ExtensionDecoder decodes fields of an extension requirement or hint into a typed value.
+type ExtensionDecoder func(fields map[string]interface{}) (interface{}, error)
+
+var (
+	extensionsMu sync.RWMutex
+	extensions   = map[string]ExtensionDecoder{}
+)
+
+// RegisterExtension registers ExtensionDecoder for the class of extension requirement or hint.
+// The class should be full IRI like "http://commonwl.org/cwltool#LoadListingRequirement",
+// which matches "cwltool:LoadListingRequirement" if "cwltool" is declared in "$namespaces".
+func RegisterExtension(class string, decoder ExtensionDecoder) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	if decoder == nil {
+		delete(extensions, class)
+		return
+	}
+	extensions[class] = decoder
+}
+
+// lookupExtension finds ExtensionDecoder registered for the class.
+func lookupExtension(classes ...string) (ExtensionDecoder, bool) {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	for _, class := range classes {
+		if decoder, ok := extensions[class]; ok {
+			return decoder, true
+		}
+	}
+	return nil, false
+}
+
+// Extension represents a requirement or hint whose class is not defined in CWL specification,
+// such as "cwltool:LoadListingRequirement" or "arv:RuntimeConstraints".
+type Extension struct {
+	// IRI is the class name expanded by "$namespaces"
+	IRI string
+	// Raw is all the fields except "class", as they are in the document
+	Raw map[string]interface{}
+	// Value is decoded by ExtensionDecoder, nil if no decoder is registered for the class
+	Value interface{}
+
+	source *Object
+}
+
+// New constructs an Extension if the class is not defined in CWL specification,
+// otherwise returns nil.
+func (_ Extension) New(class string, i interface{}) *Extension {
+	if _, defined := recordFields[class]; defined || class == "" {
+		return nil
+	}
+	dest := &Extension{IRI: class, Raw: map[string]interface{}{}}
+	if obj, ok := i.(*Object); ok {
+		dest.source = obj
+		for _, key := range obj.Keys {
+			if key != "class" {
+				dest.Raw[key] = plain(obj.Values[key])
+			}
+		}
+	}
+	return dest
+}
+
+// decode expands the class and decodes Raw by the registered ExtensionDecoder.
+func (ext *Extension) decode(class string, ns Namespaces) {
+	ext.IRI = ns.Expand(class)
+	decoder, ok := lookupExtension(ext.IRI, class)
+	if !ok {
+		return
+	}
+	v, err := decoder(ext.Raw)
+	if err != nil {
+		if ext.source != nil {
+			ext.source.failWith(err.Error())
+		}
+		return
+	}
+	ext.Value = v
+}
+
+// decodeExtensions decodes extensions in this document and its steps,
+// by namespaces inherited from the root document.
+func (root *Root) decodeExtensions(ns Namespaces) {
+	root.Hints.decodeExtensions(ns)
+	root.Requirements.decodeExtensions(ns)
+	for i := range root.Steps {
+		Requirements(root.Steps[i].Requirements).decodeExtensions(ns)
+		root.Steps[i].Hints.decodeExtensions(ns)
+		if root.Steps[i].Run.Workflow != nil {
+			root.Steps[i].Run.Workflow.decodeExtensions(ns)
+		}
+	}
+	for _, g := range root.Graphs {
+		g.decodeExtensions(ns)
+	}
+}
+
+// decodeExtensions decodes extensions in the hints.
+func (hints Hints) decodeExtensions(ns Namespaces) {
+	for i := range hints {
+		if ext := hints[i].Extension; ext != nil {
+			ext.decode(hints[i].Class, ns)
+		}
+	}
+}
+
+// decodeExtensions decodes extensions in the requirements.
+func (requirements Requirements) decodeExtensions(ns Namespaces) {
+	for i := range requirements {
+		if ext := requirements[i].Extension; ext != nil {
+			ext.decode(requirements[i].Class, ns)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package cwl
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// featureRequirements are the feature requirements in the order to be reported.
+var featureRequirements = []string{
+	"InlineJavascriptRequirement",
+	"ScatterFeatureRequirement",
+	"StepInputExpressionRequirement",
+	"SubworkflowFeatureRequirement",
+	"MultipleInputFeatureRequirement",
+}
+
+// parameterReference matches the content of a parameter reference such as "inputs.file.basename",
+// which can be evaluated without InlineJavascriptRequirement.
+// @see http://www.commonwl.org/v1.0/CommandLineTool.html#Parameter_references
+var parameterReference = regexp.MustCompile(`^\w+(\.\w+|\['([^']|\\')+'\]|\["([^"]|\\")+"\]|\[[0-9]+\])*$`)
+
+// UsesJavascript returns true if the string has any expression which requires InlineJavascriptRequirement,
+// i.e. "${...}", or "$(...)" which is not a parameter reference.
+func UsesJavascript(s string) bool {
+	for i := 0; i < len(s)-1; i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '$' && s[i+1] == '{':
+			return true
+		case s[i] == '$' && s[i+1] == '(':
+			end := closingParen(s, i+1)
+			if end < 0 || !parameterReference.MatchString(s[i+2:end]) {
+				return true
+			}
+			i = end
+		}
+	}
+	return false
+}
+
+// closingParen returns the index of ")" which closes "(" at the index, or -1 if it's not closed.
+func closingParen(s string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Expressions returns the expressions in this process,
+// except ones in steps and processes to run in steps.
+func (root *Root) Expressions() []string {
+	dest := []string{}
+	add := func(values ...string) {
+		for _, v := range values {
+			if isExpression(v) {
+				dest = append(dest, v)
+			}
+		}
+	}
+	for _, arg := range root.Arguments {
+		add(arg.Value)
+		if arg.Binding != nil {
+			add(arg.Binding.Expressions()...)
+		}
+	}
+	for _, input := range root.Inputs {
+		if input.Binding != nil {
+			add(input.Binding.Expressions()...)
+		}
+		add(input.Format)
+		add(secondaryFilesExpressions(input.SecondaryFiles)...)
+		add(typesExpressions(input.Types)...)
+	}
+	for _, output := range root.Outputs {
+		if output.Binding != nil {
+			add(output.Binding.Expressions()...)
+		}
+		add(output.Format)
+		add(secondaryFilesExpressions(output.SecondaryFiles)...)
+		add(typesExpressions(output.Types)...)
+	}
+	add(root.Stdin, root.Stdout, root.Stderr, root.Expression)
+	for _, r := range root.Requirements {
+		add(r.expressions()...)
+	}
+	for _, h := range root.Hints {
+		add(h.expressions()...)
+	}
+	return dest
+}
+
+// Expressions returns the expressions of the step itself, i.e. "valueFrom" and "when".
+func (step Step) Expressions() []string {
+	dest := []string{}
+	for _, in := range step.In {
+		if isExpression(in.ValueFrom) {
+			dest = append(dest, in.ValueFrom)
+		}
+	}
+	if step.When != "" {
+		dest = append(dest, step.When)
+	}
+	return dest
+}
+
+// expressions returns the fields of the requirement which can be expressions.
+func (r Requirement) expressions() []string {
+	dest := []string{
+		r.CoresMinExpression, r.CoresMaxExpression, r.RAMMinExpression, r.RAMMaxExpression,
+		r.TmpdirMinExpression, r.TmpdirMaxExpression, r.OutdirMinExpression, r.OutdirMaxExpression,
+		r.EnableReuseExpression, r.NetworkAccessExpression, r.TimeLimitExpression,
+	}
+	for _, e := range r.Listing {
+		dest = append(dest, e.Expression, e.Entry, e.EntryName)
+	}
+	for _, env := range r.EnvDef {
+		dest = append(dest, env.Value)
+	}
+	return dest
+}
+
+// secondaryFilesExpressions returns the fields of the secondary files which can be expressions.
+func secondaryFilesExpressions(files []SecondaryFile) []string {
+	dest := []string{}
+	for _, f := range files {
+		dest = append(dest, f.Entry, f.RequiredExpression)
+	}
+	return dest
+}
+
+// typesExpressions returns the fields of bindings in the types which can be expressions.
+func typesExpressions(types []Type) []string {
+	dest := []string{}
+	for _, t := range types {
+		if t.Binding != nil {
+			dest = append(dest, t.Binding.Expressions()...)
+		}
+		if t.OutputBinding != nil {
+			dest = append(dest, t.OutputBinding.Expressions()...)
+		}
+		for _, f := range t.Fields {
+			if f.Binding != nil {
+				dest = append(dest, f.Binding.Expressions()...)
+			}
+			if f.OutputBinding != nil {
+				dest = append(dest, f.OutputBinding.Expressions()...)
+			}
+			dest = append(dest, typesExpressions(f.Types)...)
+		}
+		dest = append(dest, typesExpressions(t.Items)...)
+	}
+	return dest
+}
+
+// features checks that features used in the process are declared by the feature requirements,
+// which are inherited from the workflows containing it, and warns unnecessary declarations.
+// It returns the features used in the process and its nested processes,
+// and whether all of the nested processes are loaded so that the unused ones are known.
+func (v *validator) features(root *Root, path string, inherited map[string]bool, stack []*Root) (map[string]bool, bool) {
+	used, complete := map[string]bool{}, true
+	for _, p := range stack {
+		if p == root {
+			return used, false
+		}
+	}
+	declared := declaredClasses(inherited, root.Requirements, root.Hints)
+	for _, expr := range root.Expressions() {
+		if UsesJavascript(expr) {
+			used["InlineJavascriptRequirement"] = true
+			if !declared["InlineJavascriptRequirement"] {
+				v.fail(path, "expression %q requires InlineJavascriptRequirement", expr)
+				break
+			}
+		}
+	}
+	for _, step := range root.Steps {
+		stepPath := join(path, "steps."+ShortName(step.ID))
+		stepDeclared := declaredClasses(declared, step.Requirements, step.Hints)
+		stepUsed := map[string]bool{}
+		require := func(class, feature string) {
+			if !stepUsed[class] && !stepDeclared[class] {
+				v.fail(stepPath, "%s requires %s", feature, class)
+			}
+			stepUsed[class] = true
+		}
+		for _, expr := range step.Expressions() {
+			if UsesJavascript(expr) {
+				require("InlineJavascriptRequirement", fmt.Sprintf("expression %q", expr))
+			}
+		}
+		if len(step.Scatter) != 0 {
+			require("ScatterFeatureRequirement", "scatter")
+		}
+		for _, in := range step.In {
+			if in.ValueFrom != "" {
+				require("StepInputExpressionRequirement", "valueFrom")
+			}
+			if len(in.Source) > 1 {
+				require("MultipleInputFeatureRequirement", "multiple sources")
+			}
+		}
+		runComplete := false
+		if run := step.Run.Workflow; run != nil {
+			if run.Class == "Workflow" {
+				require("SubworkflowFeatureRequirement", "subworkflow")
+			}
+			var nested map[string]bool
+			nested, runComplete = v.features(run, stepPath+".run", stepDeclared, append(stack, root))
+			for class := range nested {
+				stepUsed[class] = true
+			}
+		}
+		v.unnecessary(stepPath, step.Requirements, stepUsed, runComplete)
+		for class := range stepUsed {
+			used[class] = true
+		}
+		complete = complete && runComplete
+	}
+	for _, output := range root.Outputs {
+		if len(output.Source) > 1 {
+			if !declared["MultipleInputFeatureRequirement"] {
+				v.fail(join(path, "outputs."+ShortName(output.ID)), "multiple sources require MultipleInputFeatureRequirement")
+			}
+			used["MultipleInputFeatureRequirement"] = true
+		}
+	}
+	v.unnecessary(path, root.Requirements, used, complete)
+	return used, complete
+}
+
+// unnecessary warns feature requirements which are declared but not used,
+// only if all of the processes in the scope are known.
+func (v *validator) unnecessary(path string, requirements Requirements, used map[string]bool, complete bool) {
+	if !complete {
+		return
+	}
+	for _, class := range featureRequirements {
+		if requirements.Find(class) != nil && !used[class] {
+			v.warn(join(path, "requirements"), "%s is declared but not used", class)
+		}
+	}
+}
+
+// declaredClasses returns the classes of the requirements and hints, in addition to the inherited ones.
+func declaredClasses(inherited map[string]bool, requirements Requirements, hints Hints) map[string]bool {
+	dest := map[string]bool{}
+	for class := range inherited {
+		dest[class] = true
+	}
+	for _, r := range requirements {
+		dest[r.Class] = true
+	}
+	for _, h := range hints {
+		dest[h.Class] = true
+	}
+	return dest
+}
@@ -6,27 +6,48 @@ type Field struct {
 	Name    string
 	Doc     string
 	Types   []Type
-	Binding *Binding
-	Label   string
+	Binding *CommandLineBinding
+	// OutputBinding only appears in CommandOutputRecordField
+	OutputBinding *CommandOutputBinding
+	Label         string
+	// SecondaryFiles, Format, LoadContents and LoadListing appear since v1.1
+	SecondaryFiles []SecondaryFile
+	Format         string
+	LoadContents   bool
+	LoadListing    string
 }
 
 // New constructs a Field struct from any interface.
 func (_ Field) New(i interface{}) Field {
 	dest := Field{}
 	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		x.declare("RecordField")
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			switch key {
 			case "name":
-				dest.Name = v.(string)
+				dest.Name = x.String(key)
 			case "type":
 				dest.Types = Type{}.NewList(v)
 			case "inputBinding":
-				dest.Binding = Binding{}.New(v)
+				dest.Binding = CommandLineBinding{}.New(v)
 			case "outputBinding":
-				dest.Binding = Binding{}.New(v)
+				dest.OutputBinding = CommandOutputBinding{}.New(v)
+			case "secondaryFiles":
+				dest.SecondaryFiles = SecondaryFile{}.NewList(v, true)
+			case "format":
+				dest.Format = x.String(key)
+			case "loadContents":
+				dest.LoadContents = x.Bool(key)
+			case "loadListing":
+				dest.LoadListing = x.Enum(key, loadListings...)
 			}
 		}
+	case string, []interface{}:
+		dest.Types = Type{}.NewList(x)
+	case map[string]interface{}:
+		return Field{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -42,13 +63,15 @@ func (_ Fields) New(i interface{}) Fields {
 		for _, v := range x {
 			dest = append(dest, Field{}.New(v))
 		}
-	case map[string]interface{}:
-		//for key, v := range x {
-		for _, v := range x {
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			field := Field{}.New(v)
-			//field.Name = key
+			field.Name = key
 			dest = append(dest, field)
 		}
+	case map[string]interface{}:
+		return Fields{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -1,5 +1,10 @@
 package cwl
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Graphs represents "$graph" field in CWL.
 type Graphs []*Root
 
@@ -36,3 +41,162 @@ func (g Graphs) Less(i, j int) bool {
 func (g Graphs) Swap(i, j int) {
 	g[i], g[j] = g[j], g[i]
 }
+
+// Entry selects the process to run from a packed "$graph" document,
+// and returns a view of the document where the process is the root.
+// The process is selected by the fragment such as "main" or "#main" if it's given,
+// otherwise "#main", or the only Workflow, or the only process in "$graph" by default.
+// "run" of steps referring to "#xxx" are linked to the sibling processes in "$graph",
+// which modifies the processes in root.Graphs shared with the view,
+// and it returns an error if any of them is not found.
+// For a document without "$graph", it returns the document itself.
+func (root *Root) Entry(fragment string) (*Root, error) {
+	fragment = strings.TrimPrefix(fragment, "#")
+	if len(root.Graphs) == 0 {
+		if fragment == "" || matchID(root.ID, fragment) {
+			return root, nil
+		}
+		return nil, fmt.Errorf("process #%s not found: document has no $graph", fragment)
+	}
+	entry, err := root.Graphs.entry(fragment)
+	if err != nil {
+		return nil, err
+	}
+	if err := root.Graphs.link(); err != nil {
+		return nil, err
+	}
+	view := *entry
+	if view.Version == "" {
+		view.Version = root.Version
+	}
+	if len(view.Namespaces) == 0 {
+		view.Namespaces = root.Namespaces
+	}
+	if len(view.Schemas) == 0 {
+		view.Schemas = root.Schemas
+	}
+	if view.Path == "" {
+		view.Path = root.Path
+	}
+	return &view, nil
+}
+
+// entry finds the entry process by the fragment or by default.
+func (g Graphs) entry(fragment string) (*Root, error) {
+	if fragment != "" {
+		if found := g.find(fragment); found != nil {
+			return found, nil
+		}
+		return nil, fmt.Errorf("process #%s not found in $graph", fragment)
+	}
+	if found := g.find("main"); found != nil {
+		return found, nil
+	}
+	workflows := []*Root{}
+	for _, p := range g {
+		if p.Class == "Workflow" {
+			workflows = append(workflows, p)
+		}
+	}
+	switch {
+	case len(workflows) == 1:
+		return workflows[0], nil
+	case len(g) == 1:
+		return g[0], nil
+	}
+	ids := []string{}
+	for _, p := range g {
+		ids = append(ids, "#"+ShortName(strings.TrimPrefix(p.ID, "#")))
+	}
+	return nil, fmt.Errorf("ambiguous entry point of $graph: specify one of %s", strings.Join(ids, ", "))
+}
+
+// find finds the process identified by the fragment.
+func (g Graphs) find(fragment string) *Root {
+	for _, p := range g {
+		if matchID(p.ID, fragment) {
+			return p
+		}
+	}
+	return nil
+}
+
+// link sets Run.Workflow of steps referring to "#xxx" to the sibling processes,
+// and returns an error if any of them is not found in "$graph".
+func (g Graphs) link() error {
+	var err error
+	for _, p := range g {
+		if e := g.linkSteps(p.Steps); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// linkSteps links steps and steps of embedded processes to the sibling processes.
+func (g Graphs) linkSteps(steps Steps) error {
+	var err error
+	for i := range steps {
+		run := &steps[i].Run
+		switch {
+		case run.Workflow != nil:
+			// Sibling processes are linked by themselves.
+			if !containsRoot(g, run.Workflow) {
+				if e := g.linkSteps(run.Workflow.Steps); e != nil && err == nil {
+					err = e
+				}
+			}
+		case strings.HasPrefix(run.Value, "#"):
+			run.Workflow = g.find(strings.TrimPrefix(run.Value, "#"))
+			if run.Workflow == nil && err == nil {
+				err = fmt.Errorf("run \"%s\" of step %s is not found in $graph", run.Value, ShortName(steps[i].ID))
+			}
+		case strings.Contains(run.Value, "#"):
+			// Resolved by ResolveIdentifiers, e.g. "file:///packed.cwl#tool"
+			_, fragment := splitFragment(run.Value)
+			run.Workflow = g.find(fragment)
+		}
+	}
+	return err
+}
+
+// clone copies the processes and the steps of them, including embedded processes,
+// so that linking the copy doesn't modify g.
+// Steps already linked to the processes in g are linked to the copies of them.
+func (g Graphs) clone() Graphs {
+	copies := map[*Root]*Root{}
+	dest := Graphs{}
+	for _, p := range g {
+		c := *p
+		copies[p] = &c
+		dest = append(dest, &c)
+	}
+	for _, p := range dest {
+		p.Steps = cloneSteps(p.Steps, copies)
+	}
+	return dest
+}
+
+// cloneSteps copies the steps and the processes run by them, using copies made already.
+func cloneSteps(steps Steps, copies map[*Root]*Root) Steps {
+	if steps == nil {
+		return nil
+	}
+	dest := make(Steps, len(steps))
+	copy(dest, steps)
+	for i := range dest {
+		run := dest[i].Run.Workflow
+		if run == nil {
+			continue
+		}
+		if c, ok := copies[run]; ok {
+			dest[i].Run.Workflow = c
+			continue
+		}
+		c := *run
+		copies[run] = &c
+		c.Steps = cloneSteps(run.Steps, copies)
+		dest[i].Run.Workflow = &c
+	}
+	return dest
+}
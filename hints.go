@@ -1,6 +1,6 @@
 package cwl
 
-// Hints ...
+// Hints represents "hints" field in CWL.
 type Hints []Hint
 
 // New constructs "Hints" struct.
@@ -9,56 +9,67 @@ func (_ Hints) New(i interface{}) Hints {
 	switch x := i.(type) {
 	case []interface{}:
 		for _, val := range x {
-			switch e := val.(type) {
-			case map[string]interface{}:
-				hint := Hint{}.New(e)
+			switch val.(type) {
+			case *Object, map[string]interface{}:
+				hint := Hint{}.New(val)
 				dest = append(dest, hint)
 			}
 		}
-	case map[string]interface{}:
-		for key, val := range x {
-			switch e := val.(type) {
-			case map[string]interface{}:
-				hint := Hint{}.New(e)
-				hint.Class = key
-				dest = append(dest, hint)
+	case *Object:
+		for _, key := range x.Keys {
+			val := x.Values[key]
+			switch val.(type) {
+			case *Object:
+				dest = append(dest, Hint{}.newHint(key, val))
 			}
 		}
+	case map[string]interface{}:
+		return Hints{}.New(NewObject(x))
 	}
 	return dest
 }
 
-// Hint ...
+// Hint represents an element of "hints",
+// which is decoded into the same typed requirement classes as "requirements",
+// but is Soft, so that the platform may ignore it.
 type Hint struct {
-	Class      string
-	DockerPull string   // Only appears if class is "DockerRequirement"
-	CoresMin   int      // Only appears if class is "ResourceRequirement"
-	Envs       []EnvDef // Only appears if class is "EnvVarRequirement"
-	FakeField  string   // Only appears if class is "ex:BlibberBlubberFakeRequirement"
-	Import     string
+	Requirement
+	Envs []EnvDef // Same as EnvDef, only appears if class is "EnvVarRequirement"
 }
 
 // New constructs Hint from interface.
 func (_ Hint) New(i interface{}) Hint {
-	dest := Hint{}
-	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, val := range x {
-			switch key {
-			case "class":
-				dest.Class = val.(string)
-			case "dockerPull":
-				dest.DockerPull = val.(string)
-			case "coresMin":
-				dest.CoresMin = int(val.(float64))
-			case "fakeField":
-				dest.FakeField = val.(string)
-			case "envDef":
-				dest.Envs = EnvDef{}.NewList(val)
-			case "$import":
-				dest.Import = val.(string)
-			}
+	return Hint{}.newHint("", i)
+}
+
+// newHint constructs Hint of the class, as newRequirement does.
+func (_ Hint) newHint(class string, i interface{}) Hint {
+	dest := Hint{Requirement: newRequirement(class, i)}
+	dest.Soft = true
+	dest.Envs = dest.EnvDef
+	return dest
+}
+
+// Find returns the hint of the class, or nil if it's not found.
+func (hints Hints) Find(class string) *Hint {
+	for i := range hints {
+		if hints[i].Class == class {
+			return &hints[i]
 		}
 	}
-	return dest
+	return nil
+}
+
+// Lookup returns the requirement of the class which applies to this process,
+// no matter whether it's given in "requirements" or "hints".
+// Requirements take precedence over hints, and hints are marked as Soft.
+// It returns nil if neither has the class.
+func (root *Root) Lookup(class string) *Requirement {
+	if r := root.Requirements.Find(class); r != nil {
+		return r
+	}
+	if h := root.Hints.Find(class); h != nil {
+		return &h.Requirement
+	}
+	return nil
 }
@@ -0,0 +1,198 @@
+package cwl
+
+import "strings"
+
+// primitives are type names which are not references to named types.
+var primitives = []string{
+	"null", "boolean", "int", "long", "float", "double", "string",
+	"File", "Directory", "Any", "stdin", "stdout", "stderr", "array", "record", "enum",
+}
+
+// ResolveIdentifiers resolves identifiers and references in this document
+// into full URIs by Schema Salad rules, taking base as the URI of the document.
+// e.g. with base "file:///wf.cwl", input "inp1" of "#main" is "file:///wf.cwl#main/inp1",
+// and source "step1/out" of a step input is "file:///wf.cwl#main/step1/out".
+// Use ShortName to get the short local name of resolved identifiers.
+// @see http://www.commonwl.org/v1.0/SchemaSalad.html#Identifier_resolution
+func (root *Root) ResolveIdentifiers(base string) {
+	docbase, _ := splitFragment(base)
+	r := &identifiers{base: docbase, index: map[string]bool{}}
+	root.assignIDs(r, docbase, "")
+	root.resolveReferences(r)
+}
+
+// ShortName returns the short local name of an identifier,
+// e.g. "echo_out" for "file:///wf.cwl#main/step1/echo_out".
+func ShortName(id string) string {
+	_, fragment := splitFragment(id)
+	if fragment == "" {
+		fragment = id
+	}
+	if n := strings.LastIndex(fragment, "/"); n >= 0 {
+		return fragment[n+1:]
+	}
+	return fragment
+}
+
+// identifiers holds the document base URI and all the resolved identifiers.
+type identifiers struct {
+	base  string
+	index map[string]bool
+}
+
+// child resolves the name as an identifier in the scope of parent identifier.
+func (r *identifiers) child(parent, name string) string {
+	switch {
+	case name == "":
+		return ""
+	case strings.Contains(name, "://") || strings.HasPrefix(name, "_:"):
+		return name
+	case strings.HasPrefix(name, "#"):
+		return r.base + name
+	case !strings.Contains(parent, "#"):
+		return parent + "#" + name
+	}
+	return parent + "/" + name
+}
+
+// register resolves and records the identifier.
+func (r *identifiers) register(parent, name string) string {
+	id := r.child(parent, name)
+	if id != "" {
+		r.index[id] = true
+	}
+	return id
+}
+
+// scoped resolves a reference from the identifier,
+// searching upward after removing refScope segments, as Schema Salad does.
+func (r *identifiers) scoped(from, ref string, refScope int) string {
+	if strings.Contains(ref, "://") || strings.HasPrefix(ref, "#") || strings.HasPrefix(ref, "_:") {
+		return r.child(r.base, ref)
+	}
+	doc, fragment := splitFragment(from)
+	segments := []string{}
+	if fragment != "" {
+		segments = strings.Split(fragment, "/")
+	}
+	if refScope > len(segments) {
+		refScope = len(segments)
+	}
+	segments = segments[:len(segments)-refScope]
+	for {
+		candidate := doc + "#" + ref
+		if len(segments) != 0 {
+			candidate = doc + "#" + strings.Join(segments, "/") + "/" + ref
+		}
+		if r.index[candidate] || len(segments) == 0 {
+			return candidate
+		}
+		segments = segments[:len(segments)-1]
+	}
+}
+
+// typename resolves a named type reference, keeping "?" and "[]" shorthand.
+func (r *identifiers) typename(name string) string {
+	body := strings.TrimRight(name, "?[]")
+	if body == "" || contains(primitives, body) || isExpression(body) {
+		return name
+	}
+	suffix := name[len(body):]
+	if strings.Contains(body, "#") || strings.Contains(body, "://") {
+		if resolved, err := ResolveURI(r.base, body); err == nil {
+			return resolved + suffix
+		}
+		return name
+	}
+	return r.base + "#" + body + suffix
+}
+
+// types resolves names and named type references recursively.
+func (r *identifiers) types(types []Type) {
+	for i := range types {
+		types[i].Type = r.typename(types[i].Type)
+		if types[i].Name != "" {
+			types[i].Name = r.typename(types[i].Name)
+		}
+		r.types(types[i].Items)
+		for j := range types[i].Fields {
+			r.types(types[i].Fields[j].Types)
+		}
+	}
+}
+
+// assignIDs resolves identifiers of this process and its parameters and steps.
+// Embedded process without "id" is named by defaultName in the scope of parent.
+func (root *Root) assignIDs(r *identifiers, parent, defaultName string) {
+	if root.ID == "" {
+		root.ID = defaultName
+	}
+	scope := parent
+	if root.ID != "" {
+		root.ID = r.register(parent, root.ID)
+		scope = root.ID
+	}
+	for i := range root.Inputs {
+		root.Inputs[i].ID = r.register(scope, root.Inputs[i].ID)
+	}
+	for i := range root.Outputs {
+		root.Outputs[i].ID = r.register(scope, root.Outputs[i].ID)
+	}
+	for i := range root.Steps {
+		step := &root.Steps[i]
+		step.ID = r.register(scope, step.ID)
+		for j := range step.In {
+			step.In[j].ID = r.register(step.ID, step.In[j].ID)
+		}
+		for j := range step.Out {
+			step.Out[j].ID = r.register(step.ID, step.Out[j].ID)
+		}
+		if step.Run.Workflow != nil {
+			step.Run.Workflow.assignIDs(r, step.ID, "run")
+		}
+	}
+	for _, g := range root.Graphs {
+		g.assignIDs(r, r.base, "")
+	}
+}
+
+// resolveReferences resolves references to identifiers, files and named types.
+func (root *Root) resolveReferences(r *identifiers) {
+	for i := range root.Inputs {
+		r.types(root.Inputs[i].Types)
+	}
+	for i := range root.Outputs {
+		for j, src := range root.Outputs[i].Source {
+			root.Outputs[i].Source[j] = r.scoped(root.Outputs[i].ID, src, 1)
+		}
+		r.types(root.Outputs[i].Types)
+	}
+	for i := range root.Requirements {
+		r.types(root.Requirements[i].Types)
+	}
+	for i := range root.Steps {
+		step := &root.Steps[i]
+		for j := range step.In {
+			for k, src := range step.In[j].Source {
+				step.In[j].Source[k] = r.scoped(step.In[j].ID, src, 2)
+			}
+		}
+		for j, name := range step.Scatter {
+			step.Scatter[j] = r.scoped(step.ID, name, 0)
+		}
+		for j := range step.Requirements {
+			r.types(step.Requirements[j].Types)
+		}
+		if step.Run.Value != "" {
+			if resolved, err := ResolveURI(r.base, step.Run.Value); err == nil {
+				step.Run.Value = resolved
+			}
+		}
+		if step.Run.Workflow != nil {
+			step.Run.Workflow.resolveReferences(r)
+		}
+	}
+	for _, g := range root.Graphs {
+		g.resolveReferences(r)
+	}
+}
@@ -9,14 +9,17 @@ import (
 // Input represents "CommandInputParameter".
 // @see http://www.commonwl.org/v1.0/CommandLineTool.html#CommandInputParameter
 type Input struct {
-	ID             string          `json:"id"`
-	Label          string          `json:"label"`
-	Doc            string          `json:"doc"`
-	Format         string          `json:"format"`
-	Binding        *Binding        `json:"inputBinding"`
-	Default        *InputDefault   `json:"default"`
-	Types          []Type          `json:"type"`
-	SecondaryFiles []SecondaryFile `json:"secondary_files"`
+	ID             string              `json:"id"`
+	Label          string              `json:"label"`
+	Doc            string              `json:"doc"`
+	Format         string              `json:"format"`
+	Binding        *CommandLineBinding `json:"inputBinding"`
+	Default        *InputDefault       `json:"default"`
+	Types          []Type              `json:"type"`
+	SecondaryFiles []SecondaryFile     `json:"secondary_files"`
+	// LoadContents and LoadListing appear since v1.1
+	LoadContents bool
+	LoadListing  string
 	// Input.Provided is what provided by parameters.(json|yaml)
 	Provided interface{} `json:"-"`
 	// Requirement ..
@@ -28,25 +31,31 @@ type Input struct {
 func (_ Input) New(i interface{}) Input {
 	dest := Input{}
 	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		x.declare("InputParameter")
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			switch key {
 			case "id":
-				dest.ID = v.(string)
+				dest.ID = x.String(key)
 			case "type":
 				dest.Types = Type{}.NewList(v)
 			case "label":
-				dest.Label = v.(string)
+				dest.Label = x.String(key)
 			case "doc":
-				dest.Doc = v.(string)
+				dest.Doc = x.String(key)
 			case "inputBinding":
-				dest.Binding = Binding{}.New(v)
+				dest.Binding = CommandLineBinding{}.New(v)
 			case "default":
 				dest.Default = InputDefault{}.New(v)
 			case "format":
-				dest.Format = v.(string)
+				dest.Format = x.String(key)
+			case "loadContents":
+				dest.LoadContents = x.Bool(key)
+			case "loadListing":
+				dest.LoadListing = x.Enum(key, loadListings...)
 			case "secondaryFiles":
-				dest.SecondaryFiles = SecondaryFile{}.NewList(v)
+				dest.SecondaryFiles = SecondaryFile{}.NewList(v, true)
 			}
 		}
 	case string:
@@ -55,12 +64,14 @@ func (_ Input) New(i interface{}) Input {
 		for _, v := range x {
 			dest.Types = append(dest.Types, Type{}.New(v))
 		}
+	case map[string]interface{}:
+		return Input{}.New(NewObject(x))
 	}
 	return dest
 }
 
 // flatten
-func (input Input) flatten(typ Type, binding *Binding) []string {
+func (input Input) flatten(typ Type, binding *CommandLineBinding) []string {
 	flattened := []string{}
 	switch typ.Type {
 	case "int": // Array of Int
@@ -229,12 +240,15 @@ func (_ Inputs) New(i interface{}) Inputs {
 		for _, v := range x {
 			dest = append(dest, Input{}.New(v))
 		}
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			input := Input{}.New(v)
 			input.ID = key
 			dest = append(dest, input)
 		}
+	case map[string]interface{}:
+		return Inputs{}.New(NewObject(x))
 	}
 	return dest
 }
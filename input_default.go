@@ -13,12 +13,15 @@ type InputDefault struct {
 
 // New constructs new "InputDefault".
 func (_ InputDefault) New(i interface{}) *InputDefault {
-	dest := &InputDefault{Self: i, Kind: reflect.TypeOf(i).Kind()}
+	dest := &InputDefault{Self: plain(i)}
+	if dest.Self != nil {
+		dest.Kind = reflect.TypeOf(dest.Self).Kind()
+	}
 	return dest
 }
 
 // Flatten ...
-func (d *InputDefault) Flatten(binding *Binding) []string {
+func (d *InputDefault) Flatten(binding *CommandLineBinding) []string {
 	flattened := []string{}
 	switch v := d.Self.(type) {
 	case map[string]interface{}:
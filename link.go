@@ -0,0 +1,105 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Load loads the CWL document of the URI with a new Loader,
+// linking "run" of every step to the loaded process.
+func Load(uri string) (*Root, error) {
+	return NewLoader().Load(uri)
+}
+
+// LoadFile loads the CWL document of the file path with a new Loader,
+// linking "run" of every step to the loaded process.
+func LoadFile(path string) (*Root, error) {
+	return NewLoader().LoadFile(path)
+}
+
+// process loads the process referred by the URI.
+// A fragment refers to an element of "$graph" of the document.
+func (l *Loader) process(uri string) (*Root, error) {
+	docuri, fragment := splitFragment(uri)
+	root, err := l.root(docuri)
+	if err != nil || fragment == "" {
+		return root, err
+	}
+	if matchID(root.ID, fragment) {
+		return root, nil
+	}
+	if found := root.Graphs.find(fragment); found != nil {
+		return found, nil
+	}
+	return nil, fmt.Errorf("process #%s not found in %s", fragment, docuri)
+}
+
+// link sets Run.Workflow of every step of this process to the process referred by Run.Value.
+// stack is the list of processes being linked, to detect cycles.
+func (l *Loader) link(root *Root, stack []*Root) error {
+	if l.linked[root] {
+		return nil
+	}
+	for _, p := range stack {
+		if p == root {
+			return fmt.Errorf("Run cycle: %s", describeStack(append(stack, root)))
+		}
+	}
+	stack = append(stack, root)
+	for _, g := range root.Graphs {
+		if err := l.link(g, stack); err != nil {
+			return err
+		}
+	}
+	for i := range root.Steps {
+		run := &root.Steps[i].Run
+		if run.Workflow == nil && run.Value != "" {
+			uri, err := ResolveURI(root.uri(), run.Value)
+			if err != nil {
+				return err
+			}
+			if run.Workflow, err = l.process(uri); err != nil {
+				return err
+			}
+		}
+		if run.Workflow != nil {
+			if err := l.link(run.Workflow, stack); err != nil {
+				return err
+			}
+		}
+	}
+	l.linked[root] = true
+	return nil
+}
+
+// uri returns the URI of the document this process belongs to.
+func (root *Root) uri() string {
+	if strings.Contains(root.Path, "://") {
+		return root.Path
+	}
+	return FileURI(root.Path)
+}
+
+// matchID returns true if the identifier, such as "main", "#main" or "file:///wf.cwl#main",
+// is identified by the fragment.
+func matchID(id, fragment string) bool {
+	if n := strings.Index(id, "#"); n >= 0 {
+		id = id[n+1:]
+	}
+	return id != "" && id == fragment
+}
+
+// describeStack describes processes by their IDs or paths.
+func describeStack(stack []*Root) string {
+	names := []string{}
+	for _, p := range stack {
+		name := p.Path
+		if strings.Contains(p.ID, "://") {
+			name = p.ID
+		} else if p.ID != "" {
+			name += "#" + strings.TrimPrefix(p.ID, "#")
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,441 @@
+package cwl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// Fetcher fetches the content of a document specified by URI.
+type Fetcher interface {
+	Fetch(uri string) ([]byte, error)
+}
+
+// FileFetcher fetches documents of "file://" scheme from local file system.
+type FileFetcher struct{}
+
+// Fetch reads the file specified by "file://" URI.
+func (_ FileFetcher) Fetch(uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filepath.FromSlash(u.Path))
+}
+
+// HTTPFetcher fetches documents of "http://" and "https://" schemes.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+// Fetch gets the document by HTTP GET request.
+func (f HTTPFetcher) Fetch(uri string) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", uri, res.Status)
+	}
+	return ioutil.ReadAll(res.Body)
+}
+
+// Loader loads CWL documents, resolving "$import", "$include" and "$mixin" directives
+// relative to the URI of the document which contains them.
+// Fetched documents are cached, so that each URI is fetched only once.
+type Loader struct {
+	// Fetchers are keyed by URI scheme, such as "file" or "https".
+	Fetchers map[string]Fetcher
+	// Options are used to decode loaded documents.
+	Options DecodeOptions
+	// Version upgrades loaded documents to the cwlVersion as UpgradeYAML does if it's not empty,
+	// so that a workflow can use tools written in older versions.
+	Version string
+
+	documents map[string]interface{}
+	texts     map[string]string
+	roots     map[string]*Root
+	linked    map[*Root]bool
+}
+
+// NewLoader constructs a Loader which can fetch "file", "http" and "https" URIs.
+// The zero value of Loader also fetches them unless Fetchers is set.
+func NewLoader() *Loader {
+	l := &Loader{}
+	l.prepare()
+	return l
+}
+
+// prepare sets the default Fetchers and the caches if they are not set yet.
+func (l *Loader) prepare() {
+	if l.Fetchers == nil {
+		l.Fetchers = map[string]Fetcher{
+			"file":  FileFetcher{},
+			"http":  HTTPFetcher{},
+			"https": HTTPFetcher{},
+		}
+	}
+	if l.documents == nil {
+		l.documents = map[string]interface{}{}
+		l.texts = map[string]string{}
+		l.roots = map[string]*Root{}
+		l.linked = map[*Root]bool{}
+	}
+}
+
+// LoadFile loads the CWL document of the file path.
+func (l *Loader) LoadFile(path string) (*Root, error) {
+	return l.Load(FileURI(path))
+}
+
+// Load loads the CWL document of the URI, preprocessing it by Resolve,
+// and links "run" of every step to the loaded process.
+// If the URI has a fragment, it returns the process of "$graph" identified by the fragment.
+func (l *Loader) Load(uri string) (*Root, error) {
+	l.prepare()
+	root, err := l.process(uri)
+	if err != nil {
+		return root, err
+	}
+	return root, l.link(root, nil)
+}
+
+// root loads and decodes the document of the URI, using cache.
+func (l *Loader) root(uri string) (*Root, error) {
+	if root, ok := l.roots[uri]; ok {
+		return root, nil
+	}
+	doc, err := l.document(uri)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := l.Resolve(copyTree(doc), uri)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := resolved.(*Object)
+	if !ok {
+		return nil, fmt.Errorf("Parse error: document must be a mapping: %s", uri)
+	}
+	if _, ok := obj.Get("cwlVersion"); ok && l.Version != "" {
+		if err := upgrade(obj, l.Version); err != nil {
+			return nil, fmt.Errorf("%s: %v", uri, err)
+		}
+	}
+	root := NewCWL()
+	if err := root.UnmarshalObjectWithOptions(obj, l.Options); err != nil {
+		return root, err
+	}
+	root.setPath(uri)
+	l.roots[uri] = root
+	return root, nil
+}
+
+// setPath sets Path of this document and its embedded processes,
+// which is the file path for "file://" URI, or the URI itself for other schemes.
+func (root *Root) setPath(uri string) {
+	root.Path = uri
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		root.Path = filepath.FromSlash(u.Path)
+	}
+	for _, g := range root.Graphs {
+		g.setPath(uri)
+	}
+	for _, step := range root.Steps {
+		if step.Run.Workflow != nil {
+			step.Run.Workflow.setPath(uri)
+		}
+	}
+}
+
+// Resolve preprocesses the decoded tree as Schema Salad does,
+// taking base as the URI of the document:
+// "$base" overrides the base URI, "$import", "$include" and "$mixin" are resolved,
+// and "$namespaces" and "$schemas" of imported documents are merged into the root.
+func (l *Loader) Resolve(i interface{}, base string) (interface{}, error) {
+	l.prepare()
+	sc := scope{base: base, stack: []string{base}, context: newSaladContext()}
+	sc.base = sc.context.collect(i, base)
+	resolved, err := l.resolve(i, sc)
+	if err != nil {
+		return nil, err
+	}
+	if obj, ok := resolved.(*Object); ok {
+		sc.context.apply(obj)
+	}
+	return resolved, nil
+}
+
+// scope represents the document being resolved.
+type scope struct {
+	// base is the base URI to resolve relative references
+	base string
+	// stack is the list of URIs being imported, with fragments if any, to detect cycles
+	stack []string
+	// context is shared by all the documents loaded from the root
+	context *saladContext
+}
+
+// resolve resolves directives recursively.
+func (l *Loader) resolve(i interface{}, sc scope) (interface{}, error) {
+	switch x := i.(type) {
+	case *Object:
+		if v, ok := x.Get("$import"); ok {
+			ref, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: $import must be string but got %s", sc.base, kindOf(v))
+			}
+			return l.fetchAndResolve(ref, sc)
+		}
+		if v, ok := x.Get("$include"); ok {
+			ref, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: $include must be string but got %s", sc.base, kindOf(v))
+			}
+			return l.include(ref, sc.base)
+		}
+		for _, key := range x.Keys {
+			if key == "$mixin" {
+				continue
+			}
+			v, err := l.resolve(x.Values[key], sc)
+			if err != nil {
+				return nil, err
+			}
+			x.Values[key] = v
+		}
+		if v, ok := x.Get("$mixin"); ok {
+			ref, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: $mixin must be string but got %s", sc.base, kindOf(v))
+			}
+			return l.mixin(x, ref, sc)
+		}
+	case []interface{}:
+		dest := []interface{}{}
+		for _, e := range x {
+			v, err := l.resolve(e, sc)
+			if err != nil {
+				return nil, err
+			}
+			// Imported list is spliced into the parent list.
+			if list, ok := v.([]interface{}); ok && isImport(e) {
+				dest = append(dest, list...)
+			} else {
+				dest = append(dest, v)
+			}
+		}
+		return dest, nil
+	}
+	return i, nil
+}
+
+// mixin merges the object referred by "$mixin" into the object,
+// in which fields of the object take precedence over fields of the mixin.
+func (l *Loader) mixin(obj *Object, ref string, sc scope) (interface{}, error) {
+	resolved, err := l.fetchAndResolve(ref, sc)
+	if err != nil {
+		return nil, err
+	}
+	mixin, ok := resolved.(*Object)
+	if !ok {
+		return nil, fmt.Errorf("%s: $mixin must refer to a mapping but got %s", ref, kindOf(resolved))
+	}
+	dest := &Object{Values: map[string]interface{}{}, positions: map[string]position{}}
+	for _, src := range []*Object{mixin, obj} {
+		for _, key := range src.Keys {
+			if key == "$mixin" {
+				continue
+			}
+			dest.Set(key, src.Values[key])
+			dest.positions[key] = src.positions[key]
+		}
+	}
+	return dest, nil
+}
+
+// fetchAndResolve fetches the document referred by the reference and resolves it in its own scope.
+// If the reference has a fragment, only the object identified by the fragment is resolved,
+// so that a document can refer to its own objects such as "#frag".
+func (l *Loader) fetchAndResolve(ref string, sc scope) (interface{}, error) {
+	uri, err := ResolveURI(sc.base, ref)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range sc.stack {
+		if u == uri {
+			return nil, fmt.Errorf("Import cycle: %s", strings.Join(append(sc.stack, uri), " -> "))
+		}
+	}
+	docuri, fragment := splitFragment(uri)
+	doc, err := l.document(docuri)
+	if err != nil {
+		return nil, err
+	}
+	doc = copyTree(doc)
+	if obj, ok := doc.(*Object); ok {
+		if ns, ok := obj.Values["$namespaces"].(*Object); ok {
+			expandCURIEs(doc, Namespaces{}.New(ns))
+		}
+	}
+	inner := scope{stack: append(append([]string{}, sc.stack...), uri), context: sc.context}
+	inner.base = sc.context.collect(doc, docuri)
+	if fragment == "" {
+		return l.resolve(doc, inner)
+	}
+	if found := findByID(doc, fragment); found != nil {
+		return l.resolve(found, inner)
+	}
+	// The object may be in a document imported by the document.
+	resolved, err := l.resolve(doc, inner)
+	if err != nil {
+		return nil, err
+	}
+	found := findByID(resolved, fragment)
+	if found == nil {
+		return nil, fmt.Errorf("%s not found in %s", fragment, ref)
+	}
+	return found, nil
+}
+
+// include fetches the text referred by "$include".
+func (l *Loader) include(ref, base string) (interface{}, error) {
+	uri, err := ResolveURI(base, ref)
+	if err != nil {
+		return nil, err
+	}
+	if text, ok := l.texts[uri]; ok {
+		return text, nil
+	}
+	b, err := l.fetch(uri)
+	if err != nil {
+		return nil, err
+	}
+	l.texts[uri] = string(b)
+	return l.texts[uri], nil
+}
+
+// document fetches and decodes the document of the URI, using cache.
+func (l *Loader) document(uri string) (interface{}, error) {
+	if doc, ok := l.documents[uri]; ok {
+		return doc, nil
+	}
+	b, err := l.fetch(uri)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decodeYAML(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", uri, err)
+	}
+	l.documents[uri] = doc
+	return doc, nil
+}
+
+// fetch fetches the content by the Fetcher for the scheme of the URI.
+func (l *Loader) fetch(uri string) ([]byte, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	scheme := u.Scheme
+	if scheme == "" {
+		scheme = "file"
+	}
+	f, ok := l.Fetchers[scheme]
+	if !ok {
+		return nil, fmt.Errorf("no fetcher for scheme \"%s\": %s", scheme, uri)
+	}
+	return f.Fetch(uri)
+}
+
+// isImport returns true if the value is an object of "$import" directive.
+func isImport(i interface{}) bool {
+	if obj, ok := i.(*Object); ok {
+		_, found := obj.Get("$import")
+		return found
+	}
+	return false
+}
+
+// findByID finds an object which has "id" or "name" of the fragment.
+func findByID(i interface{}, fragment string) interface{} {
+	switch x := i.(type) {
+	case *Object:
+		for _, key := range []string{"id", "name"} {
+			if id, ok := x.Values[key].(string); ok {
+				if id == fragment || id == "#"+fragment || strings.HasSuffix(id, "#"+fragment) {
+					return x
+				}
+			}
+		}
+		for _, key := range x.Keys {
+			if found := findByID(x.Values[key], fragment); found != nil {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, e := range x {
+			if found := findByID(e, fragment); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// copyTree copies the decoded tree deeply, so that cached documents are not modified.
+func copyTree(i interface{}) interface{} {
+	switch x := i.(type) {
+	case *Object:
+		dest := &Object{Values: map[string]interface{}{}, positions: x.positions}
+		for _, key := range x.Keys {
+			dest.Set(key, copyTree(x.Values[key]))
+		}
+		return dest
+	case []interface{}:
+		dest := make([]interface{}, len(x))
+		for n, v := range x {
+			dest[n] = copyTree(v)
+		}
+		return dest
+	}
+	return i
+}
+
+// FileURI converts a file path to "file://" URI.
+func FileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// ResolveURI resolves the reference relative to the base URI.
+func ResolveURI(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// splitFragment splits the URI into the document URI and the fragment.
+func splitFragment(uri string) (string, string) {
+	if n := strings.Index(uri, "#"); n >= 0 {
+		return uri[:n], uri[n+1:]
+	}
+	return uri, ""
+}
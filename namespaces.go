@@ -1,5 +1,10 @@
 package cwl
 
+import (
+	"sort"
+	"strings"
+)
+
 // Namespaces ...
 type Namespaces []Namespace
 
@@ -11,12 +16,15 @@ func (_ Namespaces) New(i interface{}) Namespaces {
 		for _, v := range x {
 			dest = append(dest, Namespace{}.New(v))
 		}
-	case map[string]interface{}:
-		for key, v := range x {
-			tmp := map[string]interface{}{}
-			tmp[key] = v
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
+			tmp := &Object{Values: map[string]interface{}{}}
+			tmp.Set(key, v)
 			dest = append(dest, Namespace{}.New(tmp))
 		}
+	case map[string]interface{}:
+		return Namespaces{}.New(NewObject(x))
 	default:
 		dest = append(dest, Namespace{}.New(x))
 	}
@@ -30,10 +38,145 @@ type Namespace map[string]interface{}
 func (_ Namespace) New(i interface{}) Namespace {
 	dest := Namespace{}
 	switch x := i.(type) {
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
+			dest[key] = plain(v)
+		}
 	case map[string]interface{}:
-		for key, v := range x {
-			dest[key] = v
+		return Namespace{}.New(NewObject(x))
+	}
+	return dest
+}
+
+// Expand expands CURIE like "edam:format_2330" to full IRI
+// like "http://edamontology.org/format_2330", if the prefix is declared.
+// Otherwise, it returns the given string as it is.
+func (namespaces Namespaces) Expand(curie string) string {
+	if isExpression(curie) {
+		return curie
+	}
+	n := strings.Index(curie, ":")
+	if n < 0 || strings.HasPrefix(curie[n:], "://") {
+		return curie
+	}
+	prefix, local := curie[:n], curie[n+1:]
+	for _, ns := range namespaces {
+		if iri, ok := ns[prefix].(string); ok {
+			return iri + local
+		}
+	}
+	return curie
+}
+
+// Compact compacts full IRI to CURIE by the longest matching namespace, for display.
+// If prefixes have the same namespace, the first one in alphabetical order is used,
+// no matter in which order they are declared.
+// Otherwise, it returns the given string as it is.
+func (namespaces Namespaces) Compact(iri string) string {
+	bases := map[string]string{}
+	for _, ns := range namespaces {
+		for prefix, v := range ns {
+			// The first declaration of the prefix takes effect as in Expand.
+			if base, ok := v.(string); ok && bases[prefix] == "" {
+				bases[prefix] = base
+			}
+		}
+	}
+	prefixes := []string{}
+	for prefix := range bases {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	dest, longest := iri, 0
+	for _, prefix := range prefixes {
+		if base := bases[prefix]; len(base) > longest && strings.HasPrefix(iri, base) {
+			dest, longest = prefix+":"+strings.TrimPrefix(iri, base), len(base)
 		}
 	}
 	return dest
 }
+
+// Equal compares 2 identifiers, such as formats, by their full IRIs.
+func (namespaces Namespaces) Equal(a, b string) bool {
+	return namespaces.Expand(a) == namespaces.Expand(b)
+}
+
+// ExpandNamespaces expands CURIEs of formats, class names and type names
+// in this document and its steps, by "$namespaces" of this document.
+func (root *Root) ExpandNamespaces() {
+	root.expandNamespaces(root.Namespaces)
+}
+
+// expandNamespaces expands CURIEs by namespaces inherited from the root document.
+func (root *Root) expandNamespaces(ns Namespaces) {
+	for i := range root.Inputs {
+		root.Inputs[i].Format = ns.Expand(root.Inputs[i].Format)
+		root.Inputs[i].Types = ns.expandTypes(root.Inputs[i].Types)
+		if root.Inputs[i].Default != nil {
+			root.Inputs[i].Default.Self = ns.expandFormats(root.Inputs[i].Default.Self)
+		}
+	}
+	for i := range root.Outputs {
+		root.Outputs[i].Format = ns.Expand(root.Outputs[i].Format)
+		root.Outputs[i].Types = ns.expandTypes(root.Outputs[i].Types)
+	}
+	for i := range root.Hints {
+		root.Hints[i].Class = ns.Expand(root.Hints[i].Class)
+	}
+	ns.expandRequirements(root.Requirements)
+	for i := range root.Steps {
+		ns.expandRequirements(root.Steps[i].Requirements)
+		for j := range root.Steps[i].Hints {
+			root.Steps[i].Hints[j].Class = ns.Expand(root.Steps[i].Hints[j].Class)
+		}
+		if root.Steps[i].Run.Workflow != nil {
+			root.Steps[i].Run.Workflow.expandNamespaces(ns)
+		}
+	}
+	for _, g := range root.Graphs {
+		g.expandNamespaces(ns)
+	}
+}
+
+// expandRequirements expands class names and type names of requirements.
+func (ns Namespaces) expandRequirements(requirements Requirements) {
+	for i := range requirements {
+		requirements[i].Class = ns.Expand(requirements[i].Class)
+		requirements[i].Types = ns.expandTypes(requirements[i].Types)
+		for j := range requirements[i].Listing {
+			requirements[i].Listing[j].Format = ns.Expand(requirements[i].Listing[j].Format)
+		}
+	}
+}
+
+// expandTypes expands type names recursively.
+func (ns Namespaces) expandTypes(types []Type) []Type {
+	for i := range types {
+		types[i].Type = ns.Expand(types[i].Type)
+		types[i].Items = ns.expandTypes(types[i].Items)
+		for j := range types[i].Fields {
+			types[i].Fields[j].Types = ns.expandTypes(types[i].Fields[j].Types)
+		}
+	}
+	return types
+}
+
+// expandFormats expands "format" of File objects in a plain value, such as "default".
+func (ns Namespaces) expandFormats(i interface{}) interface{} {
+	switch x := i.(type) {
+	case map[string]interface{}:
+		for key, v := range x {
+			if format, ok := v.(string); ok && key == "format" {
+				x[key] = ns.Expand(format)
+			} else {
+				x[key] = ns.expandFormats(v)
+			}
+		}
+	case []interface{}:
+		for n, v := range x {
+			x[n] = ns.expandFormats(v)
+		}
+	}
+	return i
+}
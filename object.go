@@ -0,0 +1,412 @@
+package cwl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Object represents a mapping in CWL document.
+// Unlike map[string]interface{}, it keeps keys in the order
+// they appear in the document, so that map-form fields
+// such as "inputs", "steps" and "hints" are decoded in a stable order.
+type Object struct {
+	Keys   []string
+	Values map[string]interface{}
+
+	// path of this object in the document, e.g. "steps.step1.in"
+	path string
+	// positions of values in the source, only available if decoded from YAML/JSON
+	positions map[string]position
+	// errs is shared by all the objects in the same document
+	errs *ParseErrors
+	// record is the name of CWL record this object is decoded as
+	record string
+}
+
+// position represents line and column of a value in the source.
+type position struct {
+	Line   int
+	Column int
+}
+
+// NewObject constructs an Object from map[string]interface{}.
+// Because Go maps have no order, keys are sorted alphabetically.
+// Nested maps are converted recursively.
+func NewObject(m map[string]interface{}) *Object {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	dest := &Object{Values: map[string]interface{}{}}
+	for _, key := range keys {
+		dest.Set(key, objectify(m[key]))
+	}
+	return dest
+}
+
+// Get returns the value of the key.
+func (obj *Object) Get(key string) (interface{}, bool) {
+	v, ok := obj.Values[key]
+	return v, ok
+}
+
+// Set sets the value of the key, appending the key if it's new.
+func (obj *Object) Set(key string, v interface{}) {
+	if _, ok := obj.Values[key]; !ok {
+		obj.Keys = append(obj.Keys, key)
+	}
+	obj.Values[key] = v
+}
+
+// Delete deletes the key.
+func (obj *Object) Delete(key string) {
+	if _, ok := obj.Values[key]; !ok {
+		return
+	}
+	delete(obj.Values, key)
+	for n, k := range obj.Keys {
+		if k == key {
+			obj.Keys = append(obj.Keys[:n], obj.Keys[n+1:]...)
+			break
+		}
+	}
+}
+
+// String returns the value of the key as string.
+func (obj *Object) String(key string) string {
+	s, ok := obj.Values[key].(string)
+	if !ok {
+		obj.fail(key, "string")
+	}
+	return s
+}
+
+// Int returns the value of the key as int.
+func (obj *Object) Int(key string) int {
+	f, ok := obj.Values[key].(float64)
+	if !ok || f != float64(int(f)) {
+		obj.fail(key, "int")
+	}
+	return int(f)
+}
+
+// Bool returns the value of the key as bool.
+func (obj *Object) Bool(key string) bool {
+	b, ok := obj.Values[key].(bool)
+	if !ok {
+		obj.fail(key, "boolean")
+	}
+	return b
+}
+
+// IntOrExpression returns the value of the key as int,
+// or as expression string if it's an expression such as "$(inputs.n)".
+func (obj *Object) IntOrExpression(key string) (int, string) {
+	if s, ok := obj.Values[key].(string); ok && isExpression(s) {
+		return 0, s
+	}
+	f, ok := obj.Values[key].(float64)
+	if !ok || f != float64(int(f)) {
+		obj.fail(key, "int or expression")
+	}
+	return int(f), ""
+}
+
+// NumberOrExpression returns the value of the key as float64,
+// or as expression string if it's an expression such as "$(inputs.n)".
+func (obj *Object) NumberOrExpression(key string) (float64, string) {
+	if s, ok := obj.Values[key].(string); ok && isExpression(s) {
+		return 0, s
+	}
+	f, ok := obj.Values[key].(float64)
+	if !ok {
+		obj.fail(key, "number or expression")
+	}
+	return f, ""
+}
+
+// CeilOrExpression returns the value of the key rounded up to int,
+// or as expression string if it's an expression such as "$(inputs.n)".
+func (obj *Object) CeilOrExpression(key string) (int, string) {
+	f, expr := obj.NumberOrExpression(key)
+	return int(math.Ceil(f)), expr
+}
+
+// BoolOrExpression returns the value of the key as bool,
+// or as expression string if it's an expression such as "$(inputs.reuse)".
+func (obj *Object) BoolOrExpression(key string) (bool, string) {
+	if s, ok := obj.Values[key].(string); ok && isExpression(s) {
+		return false, s
+	}
+	b, ok := obj.Values[key].(bool)
+	if !ok {
+		obj.fail(key, "boolean or expression")
+	}
+	return b, ""
+}
+
+// Enum returns the value of the key as string, which must be one of the symbols.
+func (obj *Object) Enum(key string, symbols ...string) string {
+	s, ok := obj.Values[key].(string)
+	if !ok || !contains(symbols, s) {
+		obj.fail(key, strings.Join(symbols, ", "))
+	}
+	return s
+}
+
+// Strings returns the value of the key as a list of string,
+// converting "xxx" to ["xxx"] if it's not a sequence.
+func (obj *Object) Strings(key string) []string {
+	dest := []string{}
+	switch x := obj.Values[key].(type) {
+	case string:
+		dest = append(dest, x)
+	case []interface{}:
+		for n, v := range x {
+			if s, ok := v.(string); ok {
+				dest = append(dest, s)
+			} else {
+				obj.failAt(key+"["+strconv.Itoa(n)+"]", key, "string", v)
+			}
+		}
+	default:
+		obj.fail(key, "string or sequence of string")
+	}
+	return dest
+}
+
+// Ints returns the value of the key as a list of int.
+func (obj *Object) Ints(key string) []int {
+	dest := []int{}
+	x, ok := obj.Values[key].([]interface{})
+	if !ok {
+		obj.fail(key, "sequence of int")
+		return dest
+	}
+	for n, v := range x {
+		if f, ok := v.(float64); ok && f == float64(int(f)) {
+			dest = append(dest, int(f))
+		} else {
+			obj.failAt(key+"["+strconv.Itoa(n)+"]", key, "int", v)
+		}
+	}
+	return dest
+}
+
+// fail records a ParseError for the value of the key.
+func (obj *Object) fail(key string, expected string) {
+	obj.failAt(key, key, expected, obj.Values[key])
+}
+
+// failAt records a ParseError for the value found under the key.
+func (obj *Object) failAt(name, key string, expected string, actual interface{}) {
+	if obj.errs == nil {
+		obj.errs = &ParseErrors{}
+	}
+	e := &ParseError{
+		Path:     join(obj.path, name),
+		Expected: expected,
+		Actual:   kindOf(actual),
+	}
+	if pos, ok := obj.positions[key]; ok {
+		e.Line, e.Column = pos.Line, pos.Column
+	}
+	*obj.errs = append(*obj.errs, e)
+}
+
+// declare marks this object as a record of CWL specification,
+// such as "CommandLineBinding", so that its keys can be checked in strict mode.
+func (obj *Object) declare(record string) {
+	obj.record = record
+}
+
+// failWith records a ParseError with message for this object.
+func (obj *Object) failWith(message string) {
+	if obj.errs == nil {
+		obj.errs = &ParseErrors{}
+	}
+	*obj.errs = append(*obj.errs, &ParseError{Path: obj.path, Message: message})
+}
+
+// failKey records a ParseError with message for the value of the key.
+func (obj *Object) failKey(key, message string) {
+	if obj.errs == nil {
+		obj.errs = &ParseErrors{}
+	}
+	e := &ParseError{Path: join(obj.path, key), Message: message}
+	if pos, ok := obj.positions[key]; ok {
+		e.Line, e.Column = pos.Line, pos.Column
+	}
+	*obj.errs = append(*obj.errs, e)
+}
+
+// Errors returns all the ParseError found in the document this object belongs to.
+func (obj *Object) Errors() ParseErrors {
+	if obj.errs == nil {
+		return nil
+	}
+	return *obj.errs
+}
+
+// Len returns the number of keys.
+func (obj *Object) Len() int {
+	return len(obj.Keys)
+}
+
+// Plain converts Object to map[string]interface{} recursively.
+func (obj *Object) Plain() map[string]interface{} {
+	dest := map[string]interface{}{}
+	for _, key := range obj.Keys {
+		dest[key] = plain(obj.Values[key])
+	}
+	return dest
+}
+
+// attach sets the path and the shared error list to every *Object in the tree.
+func attach(i interface{}, path string, errs *ParseErrors) {
+	switch x := i.(type) {
+	case *Object:
+		x.path, x.errs = path, errs
+		for _, key := range x.Keys {
+			attach(x.Values[key], join(path, key), errs)
+		}
+	case []interface{}:
+		for n, v := range x {
+			attach(v, path+"["+strconv.Itoa(n)+"]", errs)
+		}
+	}
+}
+
+// join joins a path and a key by ".".
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// objectify converts every map[string]interface{} in the tree to *Object.
+func objectify(i interface{}) interface{} {
+	switch x := i.(type) {
+	case map[string]interface{}:
+		return NewObject(x)
+	case []interface{}:
+		dest := make([]interface{}, len(x))
+		for n, v := range x {
+			dest[n] = objectify(v)
+		}
+		return dest
+	}
+	return i
+}
+
+// plain converts every *Object in the tree to map[string]interface{}.
+func plain(i interface{}) interface{} {
+	switch x := i.(type) {
+	case *Object:
+		return x.Plain()
+	case []interface{}:
+		dest := make([]interface{}, len(x))
+		for n, v := range x {
+			dest[n] = plain(v)
+		}
+		return dest
+	}
+	return i
+}
+
+// decodeYAML decodes YAML (or JSON) document into the tree of
+// *Object, []interface{} and scalars.
+// Numbers are represented as float64, as encoding/json does.
+func decodeYAML(buf []byte) (interface{}, error) {
+	node := new(yaml.Node)
+	if err := yaml.Unmarshal(buf, node); err != nil {
+		return nil, err
+	}
+	return fromNode(node)
+}
+
+// fromNode converts yaml.Node to the decoded tree.
+func fromNode(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return fromNode(node.Content[0])
+	case yaml.AliasNode:
+		return fromNode(node.Alias)
+	case yaml.MappingNode:
+		dest := &Object{Values: map[string]interface{}{}, positions: map[string]position{}}
+		for n := 0; n+1 < len(node.Content); n += 2 {
+			k, v := node.Content[n], node.Content[n+1]
+			if k.Tag == "!!merge" {
+				if err := mergeNode(dest, v); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			val, err := fromNode(v)
+			if err != nil {
+				return nil, err
+			}
+			dest.Set(k.Value, val)
+			dest.positions[k.Value] = position{v.Line, v.Column}
+		}
+		return dest, nil
+	case yaml.SequenceNode:
+		dest := []interface{}{}
+		for _, e := range node.Content {
+			val, err := fromNode(e)
+			if err != nil {
+				return nil, err
+			}
+			dest = append(dest, val)
+		}
+		return dest, nil
+	}
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch x := v.(type) {
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case uint64:
+		return float64(x), nil
+	case string, bool, float64, nil:
+		return x, nil
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// mergeNode handles YAML merge key "<<", without overwriting existing keys.
+func mergeNode(dest *Object, node *yaml.Node) error {
+	val, err := fromNode(node)
+	if err != nil {
+		return err
+	}
+	sources := []interface{}{val}
+	if list, ok := val.([]interface{}); ok {
+		sources = list
+	}
+	for _, src := range sources {
+		if obj, ok := src.(*Object); ok {
+			for _, key := range obj.Keys {
+				if _, exists := dest.Get(key); !exists {
+					dest.Set(key, obj.Values[key])
+					dest.positions[key] = obj.positions[key]
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -5,41 +5,53 @@ package cwl
 // - http://www.commonwl.org/v1.0/CommandLineTool.html#CommandOutputParameter
 // - http://www.commonwl.org/v1.0/Workflow.html#WorkflowOutputParameter
 type Output struct {
-	ID             string   `json:"id"`
-	Label          string   `json:"label"`
-	Doc            []string `json:"doc"`
-	Format         string   `json:"format"`
-	Binding        *Binding `json:"outputBinding"`
-	Source         []string `json:"outputSource"`
-	Types          []Type   `json:"type"`
+	ID             string                `json:"id"`
+	Label          string                `json:"label"`
+	Doc            []string              `json:"doc"`
+	Format         string                `json:"format"`
+	Binding        *CommandOutputBinding `json:"outputBinding"`
+	Source         []string              `json:"outputSource"`
+	Types          []Type                `json:"type"`
 	SecondaryFiles []SecondaryFile
+	// LinkMerge only appears in WorkflowOutputParameter
+	LinkMerge string
+	// PickValue only appears in WorkflowOutputParameter since v1.2
+	PickValue string
 }
 
 // New constructs "Output" struct from interface.
 func (_ Output) New(i interface{}) Output {
 	dest := Output{}
 	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		x.declare("OutputParameter")
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			switch key {
 			case "id":
-				dest.ID = v.(string)
+				dest.ID = x.String(key)
 			case "type":
 				dest.Types = Type{}.NewList(v)
 			case "outputBinding":
-				dest.Binding = Binding{}.New(v)
+				dest.Binding = CommandOutputBinding{}.New(v)
 			case "outputSource":
-				dest.Source = StringArrayable(v)
+				dest.Source = x.Strings(key)
+			case "linkMerge":
+				dest.LinkMerge = x.Enum(key, linkMerges...)
+			case "pickValue":
+				dest.PickValue = x.Enum(key, pickValues...)
 			case "doc":
-				dest.Doc = StringArrayable(v)
+				dest.Doc = x.Strings(key)
 			case "format":
-				dest.Format = v.(string)
+				dest.Format = x.String(key)
 			case "secondaryFiles":
-				dest.SecondaryFiles = SecondaryFile{}.NewList(v)
+				dest.SecondaryFiles = SecondaryFile{}.NewList(v, false)
 			}
 		}
 	case string:
 		dest.Types = Type{}.NewList(x)
+	case map[string]interface{}:
+		return Output{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -55,12 +67,15 @@ func (_ Outputs) New(i interface{}) Outputs {
 		for _, v := range x {
 			dest = append(dest, Output{}.New(v))
 		}
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			output := Output{}.New(v)
 			output.ID = key
 			dest = append(dest, output)
 		}
+	case map[string]interface{}:
+		return Outputs{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -70,19 +85,11 @@ func (o Outputs) Len() int {
 	return len(o)
 }
 
-// Less for sorting
+// Less for sorting.
+// CommandOutputBinding has no position, so every output is regarded as position 0
+// and sorting keeps the order of outputs.
 func (o Outputs) Less(i, j int) bool {
-	prev, next := o[i].Binding, o[j].Binding
-	switch [2]bool{prev == nil, next == nil} {
-	case [2]bool{true, true}:
-		return false
-	case [2]bool{false, true}:
-		return prev.Position < 0
-	case [2]bool{true, false}:
-		return next.Position > 0
-	default:
-		return prev.Position <= next.Position
-	}
+	return false
 }
 
 // Swap for sorting
@@ -6,7 +6,9 @@ func StringArrayable(i interface{}) []string {
 	switch x := i.(type) {
 	case []interface{}:
 		for _, s := range x {
-			dest = append(dest, s.(string))
+			if s, ok := s.(string); ok {
+				dest = append(dest, s)
+			}
 		}
 	case string:
 		dest = append(dest, x)
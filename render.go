@@ -0,0 +1,205 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT renders the graph in Graphviz DOT language, e.g. to be converted by `dot -Tsvg`.
+// Nested workflows are rendered as clusters, scatter steps as 3D boxes,
+// and edges are labelled with the names of the ports.
+func (g *DAG) DOT() string {
+	b := &strings.Builder{}
+	b.WriteString("digraph workflow {\n")
+	b.WriteString("  rankdir=LR;\n")
+	g.writeDOT(b, "", "  ")
+	for _, e := range g.links() {
+		fmt.Fprintf(b, "  %s -> %s", dotQuote(e.From), dotQuote(e.To))
+		if label := e.label(); label != "" {
+			fmt.Fprintf(b, " [label=%s]", dotQuote(label))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeDOT writes the nodes and the clusters in the workflow of the path.
+func (g *DAG) writeDOT(b *strings.Builder, path, indent string) {
+	for _, node := range g.Nodes {
+		if node.Workflow != path {
+			continue
+		}
+		label := dotQuote(strings.Join(node.labels(), "\n"))
+		switch {
+		case node.Kind == NodeInput:
+			fmt.Fprintf(b, "%s%s [label=%s, shape=ellipse, style=filled, fillcolor=\"#94DDF4\"];\n", indent, dotQuote(node.ID), label)
+		case node.Kind == NodeOutput:
+			fmt.Fprintf(b, "%s%s [label=%s, shape=ellipse, style=filled, fillcolor=\"#94DDB4\"];\n", indent, dotQuote(node.ID), label)
+		case len(node.Step.Scatter) != 0:
+			fmt.Fprintf(b, "%s%s [label=%s, shape=box3d];\n", indent, dotQuote(node.ID), label)
+		default:
+			fmt.Fprintf(b, "%s%s [label=%s, shape=box];\n", indent, dotQuote(node.ID), label)
+		}
+	}
+	for _, child := range g.clusters(path) {
+		fmt.Fprintf(b, "%ssubgraph %s {\n", indent, dotQuote("cluster_"+child))
+		fmt.Fprintf(b, "%s  label=%s;\n", indent, dotQuote(strings.Join(g.clusterLabels(child), "\n")))
+		g.writeDOT(b, child, indent+"  ")
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+}
+
+// Mermaid renders the graph as a Mermaid flowchart, e.g. to be embedded in Markdown.
+// Nested workflows are rendered as subgraphs, scatter steps as subroutines,
+// and edges are labelled with the names of the ports.
+func (g *DAG) Mermaid() string {
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	ids := map[string]string{}
+	for n, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", n)
+	}
+	clusters := map[string]string{}
+	for n, path := range g.clusterPaths() {
+		clusters[path] = fmt.Sprintf("w%d", n)
+	}
+	g.writeMermaid(b, "", "  ", ids, clusters)
+	for _, e := range g.links() {
+		if label := e.label(); label != "" {
+			fmt.Fprintf(b, "  %s -->|%s| %s\n", ids[e.From], mermaidQuote(label), ids[e.To])
+		} else {
+			fmt.Fprintf(b, "  %s --> %s\n", ids[e.From], ids[e.To])
+		}
+	}
+	inputs, outputs := []string{}, []string{}
+	for _, node := range g.Nodes {
+		switch node.Kind {
+		case NodeInput:
+			inputs = append(inputs, ids[node.ID])
+		case NodeOutput:
+			outputs = append(outputs, ids[node.ID])
+		}
+	}
+	b.WriteString("  classDef input fill:#94DDF4\n")
+	b.WriteString("  classDef output fill:#94DDB4\n")
+	if len(inputs) != 0 {
+		fmt.Fprintf(b, "  class %s input\n", strings.Join(inputs, ","))
+	}
+	if len(outputs) != 0 {
+		fmt.Fprintf(b, "  class %s output\n", strings.Join(outputs, ","))
+	}
+	return b.String()
+}
+
+// writeMermaid writes the nodes and the subgraphs in the workflow of the path.
+func (g *DAG) writeMermaid(b *strings.Builder, path, indent string, ids, clusters map[string]string) {
+	for _, node := range g.Nodes {
+		if node.Workflow != path {
+			continue
+		}
+		label := mermaidQuote(strings.Join(node.labels(), "<br/>"))
+		switch {
+		case node.Kind != NodeStep:
+			fmt.Fprintf(b, "%s%s([%s])\n", indent, ids[node.ID], label)
+		case len(node.Step.Scatter) != 0:
+			fmt.Fprintf(b, "%s%s[[%s]]\n", indent, ids[node.ID], label)
+		default:
+			fmt.Fprintf(b, "%s%s[%s]\n", indent, ids[node.ID], label)
+		}
+	}
+	for _, child := range g.clusters(path) {
+		fmt.Fprintf(b, "%ssubgraph %s [%s]\n", indent, clusters[child], mermaidQuote(strings.Join(g.clusterLabels(child), "<br/>")))
+		g.writeMermaid(b, child, indent+"  ", ids, clusters)
+		fmt.Fprintf(b, "%send\n", indent)
+	}
+}
+
+// labels returns the lines of the label of the node, i.e. the name and the label if any.
+func (node *Node) labels() []string {
+	label := ""
+	switch node.Kind {
+	case NodeInput:
+		label = node.Input.Label
+	case NodeOutput:
+		label = node.Output.Label
+	case NodeStep:
+		label = node.Step.Label
+	}
+	if label == "" {
+		return []string{node.Name()}
+	}
+	return []string{node.Name(), label}
+}
+
+// clusterLabels returns the lines of the label of the nested workflow,
+// i.e. the name and the label of the step which runs it.
+func (g *DAG) clusterLabels(path string) []string {
+	name := path[strings.LastIndex(path, "/")+1:]
+	if step := g.Workflows[path]; step != nil && step.Label != "" {
+		return []string{name, step.Label}
+	}
+	return []string{name}
+}
+
+// clusterPaths returns the paths of all the nested workflows in the order they appear.
+func (g *DAG) clusterPaths() []string {
+	dest := []string{}
+	for _, node := range g.Nodes {
+		segments := strings.Split(node.Workflow, "/")
+		for n := range segments {
+			if path := strings.Join(segments[:n+1], "/"); path != "" && !contains(dest, path) {
+				dest = append(dest, path)
+			}
+		}
+	}
+	return dest
+}
+
+// clusters returns the paths of the nested workflows directly in the workflow of the path.
+func (g *DAG) clusters(path string) []string {
+	dest := []string{}
+	for _, child := range g.clusterPaths() {
+		parent := ""
+		if n := strings.LastIndex(child, "/"); n >= 0 {
+			parent = child[:n]
+		}
+		if parent == path {
+			dest = append(dest, child)
+		}
+	}
+	return dest
+}
+
+// links returns the edges whose both ends are nodes of the graph.
+func (g *DAG) links() []Edge {
+	dest := []Edge{}
+	for _, e := range g.Edges {
+		if g.Node(e.From) != nil && g.Node(e.To) != nil {
+			dest = append(dest, e)
+		}
+	}
+	return dest
+}
+
+// label returns the label of the edge, e.g. "lines → file" for the ports.
+func (e Edge) label() string {
+	ports := []string{}
+	for _, port := range []string{e.FromPort, e.ToPort} {
+		if port != "" {
+			ports = append(ports, port)
+		}
+	}
+	return strings.Join(ports, " → ")
+}
+
+// dotQuote quotes the string as an ID of DOT language.
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// mermaidQuote quotes the string as a text of Mermaid.
+func mermaidQuote(s string) string {
+	return `"` + strings.Replace(s, `"`, "#quot;", -1) + `"`
+}
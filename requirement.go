@@ -11,22 +11,78 @@ type Requirement struct {
 	EnvVarRequirement
 	ShellCommandRequirement
 	ResourceRequirement
+	LoadListingRequirement
+	WorkReuse
+	NetworkAccess
+	InplaceUpdateRequirement
+	ToolTimeLimit
 	Import string
+	// Extension only appears if class is not defined in CWL specification
+	Extension *Extension
+	// Soft is true if this is given in "hints", which the platform may ignore
+	Soft bool
 }
 
 // New constructs "Requirement" struct from interface.
 func (_ Requirement) New(i interface{}) Requirement {
-	dest := Requirement{}
+	return newRequirement("", i)
+}
+
+// newRequirement constructs "Requirement" of the class,
+// which is the key of map-form "requirements", or "class" field of the object.
+// Fields of extension classes are left to Extension.
+func newRequirement(class string, i interface{}) Requirement {
+	dest := Requirement{Class: class}
 	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		// The key of map-form "requirements" takes precedence over "class" field.
+		if s, ok := x.Values["class"].(string); ok && class == "" {
+			class = s
+		}
+		_, known := recordFields[class]
+		for _, key := range x.Keys {
+			v := x.Values[key]
+			if !known && class != "" && key != "class" && key != "$import" {
+				continue
+			}
 			switch key {
 			case "class":
-				dest.Class = v.(string)
+				if dest.Class == "" {
+					dest.Class = x.String(key)
+				}
 			case "dockerPull":
-				dest.DockerPull = v.(string)
+				dest.DockerPull = x.String(key)
+			case "dockerLoad":
+				dest.DockerLoad = x.String(key)
+			case "dockerFile":
+				// {"$include": "Dockerfile"} is resolved only by Loader.
+				if _, ok := v.(*Object); !ok {
+					dest.DockerFile = x.String(key)
+				}
+			case "dockerImport":
+				dest.DockerImport = x.String(key)
+			case "dockerImageId":
+				dest.DockerImageID = x.String(key)
 			case "dockerOutputDirectory":
-				dest.DockerOutputDirectory = v.(string)
+				dest.DockerOutputDirectory = x.String(key)
+			case "packages":
+				dest.Packages = SoftwarePackage{}.NewList(v)
+			case "coresMin":
+				dest.CoresMin, dest.CoresMinExpression = x.NumberOrExpression(key)
+			case "coresMax":
+				dest.CoresMax, dest.CoresMaxExpression = x.NumberOrExpression(key)
+			case "ramMin":
+				dest.RAMMin, dest.RAMMinExpression = x.CeilOrExpression(key)
+			case "ramMax":
+				dest.RAMMax, dest.RAMMaxExpression = x.CeilOrExpression(key)
+			case "tmpdirMin":
+				dest.TmpdirMin, dest.TmpdirMinExpression = x.CeilOrExpression(key)
+			case "tmpdirMax":
+				dest.TmpdirMax, dest.TmpdirMaxExpression = x.CeilOrExpression(key)
+			case "outdirMin":
+				dest.OutdirMin, dest.OutdirMinExpression = x.CeilOrExpression(key)
+			case "outdirMax":
+				dest.OutdirMax, dest.OutdirMaxExpression = x.CeilOrExpression(key)
 			case "types":
 				dest.Types = Type{}.NewList(v)
 			case "expressionLib":
@@ -35,14 +91,36 @@ func (_ Requirement) New(i interface{}) Requirement {
 				dest.EnvDef = EnvDef{}.NewList(v)
 			case "listing":
 				dest.Listing = Entry{}.NewList(v)
+			case "loadListing":
+				dest.LoadListing = x.Enum(key, loadListings...)
+			case "enableReuse":
+				dest.EnableReuse, dest.EnableReuseExpression = x.BoolOrExpression(key)
+			case "networkAccess":
+				dest.NetworkAccess.NetworkAccess, dest.NetworkAccessExpression = x.BoolOrExpression(key)
+			case "inplaceUpdate":
+				dest.InplaceUpdate = x.Bool(key)
+			case "timelimit":
+				dest.TimeLimit, dest.TimeLimitExpression = x.IntOrExpression(key)
 			case "$import":
-				dest.Import = v.(string)
+				dest.Import = x.String(key)
 			}
 		}
+		dest.setDefaults(x)
+		x.declare(dest.Class)
+	case map[string]interface{}:
+		return newRequirement(class, NewObject(x))
 	}
+	dest.Extension = Extension{}.New(dest.Class, i)
 	return dest
 }
 
+// setDefaults sets default values of fields which the requirement omits.
+func (r *Requirement) setDefaults(x *Object) {
+	if _, ok := x.Get("enableReuse"); !ok && r.Class == "WorkReuse" {
+		r.EnableReuse = true
+	}
+}
+
 // Requirements represents "requirements" field in CWL.
 type Requirements []Requirement
 
@@ -54,16 +132,28 @@ func (_ Requirements) New(i interface{}) Requirements {
 		for _, r := range x {
 			dest = append(dest, Requirement{}.New(r))
 		}
-	case map[string]interface{}:
-		for key, v := range x {
-			r := Requirement{}.New(v)
-			r.Class = key
-			dest = append(dest, r)
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
+			dest = append(dest, newRequirement(key, v))
 		}
+	case map[string]interface{}:
+		return Requirements{}.New(NewObject(x))
 	}
 	return dest
 }
 
+// Find returns the requirement of the class, or nil if it's not found.
+// e.g. Find("ShellCommandRequirement") tells if the tool requires shell.
+func (reqs Requirements) Find(class string) *Requirement {
+	for i := range reqs {
+		if reqs[i].Class == class {
+			return &reqs[i]
+		}
+	}
+	return nil
+}
+
 // InlineJavascriptRequirement is supposed to be embeded to Requirement.
 // @see http://www.commonwl.org/v1.0/CommandLineTool.html#InlineJavascriptRequirement
 type InlineJavascriptRequirement struct {
@@ -93,14 +183,16 @@ func (_ JavascriptExpression) New(i interface{}) JavascriptExpression {
 	case string:
 		dest.Kind = "$execute"
 		dest.Value = x
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		for _, key := range x.Keys {
 			switch key {
 			case "$include":
 				dest.Kind = key
-				dest.Value = v.(string)
+				dest.Value = x.String(key)
 			}
 		}
+	case map[string]interface{}:
+		return JavascriptExpression{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -135,9 +227,61 @@ type SoftwarePackage struct {
 	Specs    []string
 }
 
+// NewList constructs a list of SoftwarePackage from list-form or map-form "packages".
+// In map-form, the key is "package" and the value is "specs" or the other fields.
+func (_ SoftwarePackage) NewList(i interface{}) []SoftwarePackage {
+	dest := []SoftwarePackage{}
+	switch x := i.(type) {
+	case []interface{}:
+		for _, v := range x {
+			dest = append(dest, SoftwarePackage{}.New(v))
+		}
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
+			p := SoftwarePackage{Package: key}
+			switch v.(type) {
+			case *Object:
+				p = SoftwarePackage{}.New(v)
+				p.Package = key
+			default:
+				p.Specs = x.Strings(key)
+			}
+			dest = append(dest, p)
+		}
+	case map[string]interface{}:
+		return SoftwarePackage{}.NewList(NewObject(x))
+	}
+	return dest
+}
+
+// New constructs a SoftwarePackage from interface.
+func (_ SoftwarePackage) New(i interface{}) SoftwarePackage {
+	dest := SoftwarePackage{}
+	switch x := i.(type) {
+	case *Object:
+		x.declare("SoftwarePackage")
+		for _, key := range x.Keys {
+			switch key {
+			case "package":
+				dest.Package = x.String(key)
+			case "version":
+				dest.Versions = x.Strings(key)
+			case "specs":
+				dest.Specs = x.Strings(key)
+			}
+		}
+	case map[string]interface{}:
+		return SoftwarePackage{}.New(NewObject(x))
+	}
+	return dest
+}
+
 // InitialWorkDirRequirement is supposed to be embeded to Requirement.
+// Listing can mix File, Directory, Dirent and expressions.
 // @see http://www.commonwl.org/v1.0/CommandLineTool.html#InitialWorkDirRequirement
 type InitialWorkDirRequirement struct {
+	// Listing has an Entry of Expression if "listing" is an expression
 	Listing []Entry
 }
 
@@ -148,13 +292,68 @@ type EnvVarRequirement struct {
 }
 
 // ShellCommandRequirement is supposed to be embeded to Requirement.
+// It has no field, use Requirements.Find to know if it's required.
 // @see http://www.commonwl.org/v1.0/CommandLineTool.html#ShellCommandRequirement
 type ShellCommandRequirement struct {
 }
 
 // ResourceRequirement is supposed to be embeded to Requirement.
 // @see http://www.commonwl.org/v1.0/CommandLineTool.html#ResourceRequirement
+// Each field can be an expression, which appears in the field with "Expression" suffix.
+// RAM, tmpdir and outdir are in mebibytes.
+// Since v1.2, the values can be fractional: RAM, tmpdir and outdir are rounded up,
+// and cores are kept as they are.
 type ResourceRequirement struct {
-	CoresMin int
-	CoresMax int
+	CoresMin            float64
+	CoresMinExpression  string
+	CoresMax            float64
+	CoresMaxExpression  string
+	RAMMin              int
+	RAMMinExpression    string
+	RAMMax              int
+	RAMMaxExpression    string
+	TmpdirMin           int
+	TmpdirMinExpression string
+	TmpdirMax           int
+	TmpdirMaxExpression string
+	OutdirMin           int
+	OutdirMinExpression string
+	OutdirMax           int
+	OutdirMaxExpression string
+}
+
+// loadListings are symbols of LoadListingEnum.
+var loadListings = []string{"no_listing", "shallow_listing", "deep_listing"}
+
+// LoadListingRequirement is supposed to be embeded to Requirement.
+// @see https://www.commonwl.org/v1.1/CommandLineTool.html#LoadListingRequirement
+type LoadListingRequirement struct {
+	LoadListing string
+}
+
+// WorkReuse is supposed to be embeded to Requirement.
+// @see https://www.commonwl.org/v1.1/CommandLineTool.html#WorkReuse
+type WorkReuse struct {
+	EnableReuse           bool
+	EnableReuseExpression string
+}
+
+// NetworkAccess is supposed to be embeded to Requirement.
+// @see https://www.commonwl.org/v1.1/CommandLineTool.html#NetworkAccess
+type NetworkAccess struct {
+	NetworkAccess           bool
+	NetworkAccessExpression string
+}
+
+// InplaceUpdateRequirement is supposed to be embeded to Requirement.
+// @see https://www.commonwl.org/v1.1/CommandLineTool.html#InplaceUpdateRequirement
+type InplaceUpdateRequirement struct {
+	InplaceUpdate bool
+}
+
+// ToolTimeLimit is supposed to be embeded to Requirement.
+// @see https://www.commonwl.org/v1.1/CommandLineTool.html#ToolTimeLimit
+type ToolTimeLimit struct {
+	TimeLimit           int // in seconds, 0 means no limit
+	TimeLimitExpression string
 }
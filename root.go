@@ -1,13 +1,9 @@
 package cwl
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-
-	"github.com/otiai10/yaml2json"
 )
 
 // NewCWL ...
@@ -42,38 +38,82 @@ type Root struct {
 	ID           string // ID only appears if this Root is a step in "steps"
 	Expression   string // appears only if Class is "ExpressionTool"
 
+	// Exit codes, which appear only if Class is "CommandLineTool"
+	SuccessCodes       []int
+	TemporaryFailCodes []int
+	PermanentFailCodes []int
+
 	// Path
 	Path string `json:"-"`
 }
 
 // UnmarshalMap decode map[string]interface{} to *Root.
+// Since Go maps have no order, map-form fields are decoded in alphabetical order.
+// Use Decode or UnmarshalJSON to keep the order of the document.
 func (root *Root) UnmarshalMap(docs map[string]interface{}) error {
-	for key, val := range docs {
+	return root.UnmarshalObject(NewObject(docs))
+}
+
+// UnmarshalObject decode *Object to *Root.
+// It doesn't stop at the first invalid value, but returns ParseErrors
+// which contains all the errors found in the document.
+func (root *Root) UnmarshalObject(docs *Object) error {
+	return root.UnmarshalObjectWithOptions(docs, DecodeOptions{})
+}
+
+// UnmarshalObjectWithOptions decode *Object to *Root with DecodeOptions.
+// In strict mode, it returns UnknownFields if the document is valid
+// but has keys not defined in CWL specification.
+func (root *Root) UnmarshalObjectWithOptions(docs *Object, opts DecodeOptions) error {
+	if version, ok := docs.Values["cwlVersion"].(string); ok && atLeast(version, Version11) {
+		expandSecondaryFiles(docs)
+	}
+	errs := &ParseErrors{}
+	attach(docs, "", errs)
+	root.unmarshal(docs)
+	root.decodeExtensions(root.Namespaces)
+	checkVersion(docs, versionScope{version: root.Version})
+	root.applyVersion("")
+	if len(*errs) != 0 {
+		return *errs
+	}
+	if opts.Strict {
+		if unknowns := checkStrict(docs, root.Namespaces); len(unknowns) != 0 {
+			return unknowns
+		}
+	}
+	return nil
+}
+
+// unmarshal decodes *Object to *Root, recording errors to the object.
+func (root *Root) unmarshal(docs *Object) {
+	for _, key := range docs.Keys {
+		val := docs.Values[key]
 		switch key {
 		case "cwlVersion":
-			root.Version = val.(string)
+			root.Version = docs.String(key)
 		case "class":
-			root.Class = val.(string)
+			root.Class = docs.String(key)
 		case "hints":
 			root.Hints = root.Hints.New(val)
 		case "doc":
-			root.Doc = val.(string)
+			root.Doc = docs.String(key)
 		case "baseCommand":
-			root.BaseCommands = root.BaseCommands.New(val)
+			root.BaseCommands = docs.Strings(key)
 		case "arguments":
 			root.Arguments = root.Arguments.New(val)
 		case "$namespaces":
 			root.Namespaces = root.Namespaces.New(val)
 		case "$schemas":
-			root.Schemas = root.Schemas.New(val)
+			root.Schemas = docs.Strings(key)
 		case "$graph":
 			root.Graphs = root.Graphs.New(val)
 		case "stdin":
-			root.Stdin = val.(string)
+			root.Stdin = docs.String(key)
 		case "stdout":
-			root.Stdout = val.(string)
+			root.Stdout = docs.String(key)
 		case "stderr":
-			root.Stderr = val.(string)
+			root.Stderr = docs.String(key)
 		case "inputs":
 			root.Inputs = root.Inputs.New(val)
 		case "outputs":
@@ -83,42 +123,54 @@ func (root *Root) UnmarshalMap(docs map[string]interface{}) error {
 		case "steps":
 			root.Steps = root.Steps.New(val)
 		case "id":
-			root.ID = val.(string)
+			root.ID = docs.String(key)
 		case "expression":
-			root.Expression = val.(string)
+			root.Expression = docs.String(key)
+		case "successCodes":
+			root.SuccessCodes = docs.Ints(key)
+		case "temporaryFailCodes":
+			root.TemporaryFailCodes = docs.Ints(key)
+		case "permanentFailCodes":
+			root.PermanentFailCodes = docs.Ints(key)
 		}
 	}
-	return nil
+	docs.declare(root.Class)
+	if root.Class == "Workflow" {
+		root.checkConditionals(docs)
+	}
 }
 
 // UnmarshalJSON ...
 func (root *Root) UnmarshalJSON(b []byte) error {
-	docs := map[string]interface{}{}
-	if err := json.Unmarshal(b, &docs); err != nil {
-		return err
-	}
-	return root.UnmarshalMap(docs)
+	return root.unmarshalBytes(b, DecodeOptions{})
 }
 
 // Decode decodes specified file to this root
-func (root *Root) Decode(r io.Reader) (err error) {
-	defer func() {
-		if e := recover(); e != nil {
-			err = fmt.Errorf("Parse error: %v", e)
-		}
-	}()
+// If the document has invalid values, it returns ParseErrors.
+func (root *Root) Decode(r io.Reader) error {
+	return root.DecodeWithOptions(r, DecodeOptions{})
+}
+
+// DecodeWithOptions decodes specified file to this root with DecodeOptions.
+func (root *Root) DecodeWithOptions(r io.Reader, opts DecodeOptions) error {
 	buf, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
 	}
-	buf, err = yaml2json.Y2J(bytes.NewReader(buf))
+	return root.unmarshalBytes(buf, opts)
+}
+
+// unmarshalBytes decodes YAML or JSON bytes to this root.
+func (root *Root) unmarshalBytes(buf []byte, opts DecodeOptions) error {
+	docs, err := decodeYAML(buf)
 	if err != nil {
 		return err
 	}
-	if err = json.Unmarshal(buf, root); err != nil {
-		return err
+	obj, ok := docs.(*Object)
+	if !ok {
+		return fmt.Errorf("Parse error: document must be a mapping")
 	}
-	return nil
+	return root.UnmarshalObjectWithOptions(obj, opts)
 }
 
 // AsStep constructs Root as a step of "steps" from interface.
@@ -127,11 +179,10 @@ func (root *Root) AsStep(i interface{}) *Root {
 	switch x := i.(type) {
 	case string:
 		dest.ID = x
+	case *Object:
+		dest.unmarshal(x)
 	case map[string]interface{}:
-		err := dest.UnmarshalMap(x)
-		if err != nil {
-			panic(fmt.Sprintf("Failed to parse step as CWL.Root: %v", err))
-		}
+		return root.AsStep(NewObject(x))
 	}
 	return dest
 }
@@ -0,0 +1,105 @@
+package cwl
+
+// saladContext collects document-level directives of Schema Salad,
+// "$namespaces" and "$schemas", from all the documents loaded from the root.
+// @see https://www.commonwl.org/v1.0/SchemaSalad.html#Document_preprocessing
+type saladContext struct {
+	namespaces *Object
+	schemas    []interface{}
+}
+
+// newSaladContext constructs an empty saladContext.
+func newSaladContext() *saladContext {
+	return &saladContext{namespaces: &Object{Values: map[string]interface{}{}}}
+}
+
+// collect removes "$base", "$namespaces" and "$schemas" from the document,
+// keeping namespaces and schemas in the context, and returns the base URI of the document.
+func (c *saladContext) collect(doc interface{}, uri string) string {
+	obj, ok := doc.(*Object)
+	if !ok {
+		return uri
+	}
+	base := uri
+	if b, ok := obj.Values["$base"].(string); ok {
+		if resolved, err := ResolveURI(uri, b); err == nil {
+			base = resolved
+		}
+	}
+	if ns, ok := obj.Values["$namespaces"].(*Object); ok {
+		for _, prefix := range ns.Keys {
+			// Namespaces declared by the root document take precedence,
+			// while CURIEs of imported documents are already expanded by their own.
+			if _, exists := c.namespaces.Get(prefix); !exists {
+				c.namespaces.Set(prefix, ns.Values[prefix])
+			}
+		}
+	}
+	if schemas, ok := obj.Values["$schemas"].([]interface{}); ok {
+		for _, s := range schemas {
+			if ref, ok := s.(string); ok {
+				if resolved, err := ResolveURI(base, ref); err == nil {
+					s = resolved
+				}
+			}
+			if !containsValue(c.schemas, s) {
+				c.schemas = append(c.schemas, s)
+			}
+		}
+	}
+	obj.Delete("$base")
+	obj.Delete("$namespaces")
+	obj.Delete("$schemas")
+	return base
+}
+
+// expandCURIEs expands CURIEs of "class", "type", "items" and "format" in the imported document
+// by its own "$namespaces", before they are merged into the root which may declare the prefixes differently.
+func expandCURIEs(i interface{}, ns Namespaces) {
+	switch x := i.(type) {
+	case *Object:
+		for _, key := range x.Keys {
+			switch v := x.Values[key].(type) {
+			case string:
+				if key == "class" || key == "type" || key == "items" || key == "format" {
+					x.Values[key] = ns.Expand(v)
+				}
+			case []interface{}:
+				if key == "type" || key == "items" || key == "format" {
+					for n, e := range v {
+						if s, ok := e.(string); ok {
+							v[n] = ns.Expand(s)
+						}
+					}
+				}
+				expandCURIEs(v, ns)
+			default:
+				expandCURIEs(v, ns)
+			}
+		}
+	case []interface{}:
+		for _, e := range x {
+			expandCURIEs(e, ns)
+		}
+	}
+}
+
+// apply sets collected namespaces and schemas to the root document.
+func (c *saladContext) apply(root *Object) {
+	if c.namespaces.Len() != 0 {
+		root.Set("$namespaces", c.namespaces)
+	}
+	if len(c.schemas) != 0 {
+		root.Set("$schemas", c.schemas)
+	}
+}
+
+// containsValue returns true if the list contains the value.
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
@@ -9,7 +9,9 @@ func (_ Schemas) New(i interface{}) Schemas {
 	switch x := i.(type) {
 	case []interface{}:
 		for _, v := range x {
-			dest = append(dest, v.(string))
+			if s, ok := v.(string); ok {
+				dest = append(dest, s)
+			}
 		}
 	}
 	return dest
@@ -1,18 +1,91 @@
 package cwl
 
+import "strings"
+
 // SecondaryFile represents an element of "secondaryFiles".
+// Since v1.1, it can be SecondaryFileSchema which has "pattern" and "required".
+// @see https://www.commonwl.org/v1.1/CommandLineTool.html#SecondaryFileSchema
 type SecondaryFile struct {
+	// Entry is the pattern such as ".bai" or "^.fai", or an expression.
 	Entry string
+	// Required is true if the secondary file must exist.
+	Required bool
+	// RequiredExpression only appears if "required" is an expression.
+	RequiredExpression string
 }
 
 // NewList constructs list of "SecondaryFile".
-func (_ SecondaryFile) NewList(i interface{}) []SecondaryFile {
+// required is the default of "required", which is true for inputs and false for outputs.
+func (_ SecondaryFile) NewList(i interface{}, required bool) []SecondaryFile {
 	dest := []SecondaryFile{}
 	switch x := i.(type) {
 	case []interface{}:
 		for _, v := range x {
-			dest = append(dest, SecondaryFile{Entry: v.(string)})
+			dest = append(dest, SecondaryFile{}.New(v, required))
 		}
+	default:
+		dest = append(dest, SecondaryFile{}.New(x, required))
 	}
 	return dest
 }
+
+// New constructs "SecondaryFile" from string or SecondaryFileSchema.
+func (_ SecondaryFile) New(i interface{}, required bool) SecondaryFile {
+	dest := SecondaryFile{Required: required}
+	switch x := i.(type) {
+	case string:
+		dest.Entry = x
+	case *Object:
+		x.declare("SecondaryFileSchema")
+		for _, key := range x.Keys {
+			switch key {
+			case "pattern":
+				dest.Entry = x.String(key)
+			case "required":
+				dest.Required, dest.RequiredExpression = x.BoolOrExpression(key)
+			}
+		}
+	case map[string]interface{}:
+		return SecondaryFile{}.New(NewObject(x), required)
+	}
+	return dest
+}
+
+// expandSecondaryFiles rewrites patterns with "?" suffix in "secondaryFiles" of the tree
+// to SecondaryFileSchema which is not required, as the syntax of v1.1 means.
+// Expressions and "default" values are left as they are.
+func expandSecondaryFiles(i interface{}) {
+	switch x := i.(type) {
+	case *Object:
+		for _, key := range x.Keys {
+			switch key {
+			case "default":
+			case "secondaryFiles":
+				x.Values[key] = optionalPatterns(x.Values[key])
+			default:
+				expandSecondaryFiles(x.Values[key])
+			}
+		}
+	case []interface{}:
+		for _, v := range x {
+			expandSecondaryFiles(v)
+		}
+	}
+}
+
+// optionalPatterns rewrites the pattern, or the list of patterns, with "?" suffix.
+func optionalPatterns(i interface{}) interface{} {
+	switch x := i.(type) {
+	case string:
+		if strings.HasSuffix(x, "?") && !isExpression(x) {
+			return &Object{Keys: []string{"pattern", "required"}, Values: map[string]interface{}{"pattern": strings.TrimSuffix(x, "?"), "required": false}}
+		}
+	case []interface{}:
+		dest := make([]interface{}, len(x))
+		for n, v := range x {
+			dest[n] = optionalPatterns(v)
+		}
+		return dest
+	}
+	return i
+}
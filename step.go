@@ -12,12 +12,17 @@ func (_ Steps) New(i interface{}) Steps {
 			s := Step{}.New(v)
 			dest = append(dest, s)
 		}
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			s := Step{}.New(v)
-			s.ID = key
+			if s.ID == "" {
+				s.ID = key
+			}
 			dest = append(dest, s)
 		}
+	case map[string]interface{}:
+		return Steps{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -26,12 +31,17 @@ func (_ Steps) New(i interface{}) Steps {
 // @see http://www.commonwl.org/v1.0/Workflow.html#WorkflowStep
 type Step struct {
 	ID            string
+	Label         string
+	Doc           string
 	In            StepInputs
 	Out           []StepOutput
 	Run           Run
 	Requirements  []Requirement
+	Hints         Hints
 	Scatter       []string
 	ScatterMethod string
+	// When is the condition to run this step, since v1.2
+	When string
 }
 
 // Run `run` accept string | CommandLineTool | ExpressionTool | Workflow
@@ -44,17 +54,25 @@ type Run struct {
 func (_ Step) New(i interface{}) Step {
 	dest := Step{}
 	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		x.declare("WorkflowStep")
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			switch key {
 			case "id":
-				dest.ID = v.(string)
+				dest.ID = x.String(key)
+			case "label":
+				dest.Label = x.String(key)
+			case "doc":
+				dest.Doc = x.String(key)
 			case "run":
 				switch x2 := v.(type) {
 				case string:
 					dest.Run.Value = x2
-				case map[string]interface{}:
+				case *Object:
 					dest.Run.Workflow = dest.Run.Workflow.AsStep(v)
+				default:
+					x.fail(key, "string or mapping")
 				}
 			case "in":
 				dest.In = StepInput{}.NewList(v)
@@ -62,12 +80,21 @@ func (_ Step) New(i interface{}) Step {
 				dest.Out = StepOutput{}.NewList(v)
 			case "requirements":
 				dest.Requirements = Requirements{}.New(v)
+			case "hints":
+				dest.Hints = Hints{}.New(v)
 			case "scatter":
-				dest.Scatter = StringArrayable(v)
+				dest.Scatter = x.Strings(key)
 			case "scatterMethod":
-				dest.ScatterMethod = v.(string)
+				dest.ScatterMethod = x.String(key)
+			case "when":
+				dest.When = x.String(key)
+				if s, ok := v.(string); ok && !isExpression(s) {
+					x.failKey(key, "\"when\" must be an expression")
+				}
 			}
 		}
+	case map[string]interface{}:
+		return Step{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -1,59 +1,57 @@
 package cwl
 
+// linkMerges are symbols of LinkMergeMethod.
+// @see http://www.commonwl.org/v1.0/Workflow.html#LinkMergeMethod
+var linkMerges = []string{"merge_nested", "merge_flattened"}
+
 // StepInput represents WorkflowStepInput.
 // @see http://www.commonwl.org/v1.0/Workflow.html#WorkflowStepInput
 type StepInput struct {
-	ID        string
+	ID string
+	// Label appears since v1.1
+	Label     string
 	Source    []string
 	LinkMerge string
 	Default   *InputDefault
 	ValueFrom string
+	// PickValue appears since v1.2
+	PickValue string
+	// LoadContents and LoadListing appear since v1.1
+	LoadContents bool
+	LoadListing  string
 }
 
 // New constructs a StepInput struct from any interface.
 func (_ StepInput) New(i interface{}) StepInput {
 	dest := StepInput{}
 	switch x := i.(type) {
-	case map[string]interface{}:
-		for key, v := range x {
-			if dest.ID == "" {
-				dest.ID = key
-			}
-
-			if key == "id" {
-				dest.ID = v.(string)
-			} else {
-				switch e := v.(type) {
-				case string:
-					dest.Source = []string{e}
-				case []interface{}:
-					for _, s := range e {
-						dest.Source = append(dest.Source, s.(string))
-					}
-				case map[string]interface{}:
-					for key, v := range e {
-						switch key {
-						case "id":
-							dest.ID = v.(string)
-						case "source":
-							if list, ok := v.([]interface{}); ok {
-								for _, s := range list {
-									dest.Source = append(dest.Source, s.(string))
-								}
-							} else {
-								dest.Source = append(dest.Source, v.(string))
-							}
-						case "linkMerge":
-							dest.LinkMerge = v.(string)
-						case "default":
-							dest.Default = InputDefault{}.New(v)
-						case "valueFrom":
-							dest.ValueFrom = v.(string)
-						}
-					}
-				}
+	case *Object:
+		x.declare("WorkflowStepInput")
+		for _, key := range x.Keys {
+			v := x.Values[key]
+			switch key {
+			case "id":
+				dest.ID = x.String(key)
+			case "label":
+				dest.Label = x.String(key)
+			case "source":
+				dest.Source = x.Strings(key)
+			case "linkMerge":
+				dest.LinkMerge = x.Enum(key, linkMerges...)
+			case "default":
+				dest.Default = InputDefault{}.New(v)
+			case "valueFrom":
+				dest.ValueFrom = x.String(key)
+			case "pickValue":
+				dest.PickValue = x.Enum(key, pickValues...)
+			case "loadContents":
+				dest.LoadContents = x.Bool(key)
+			case "loadListing":
+				dest.LoadListing = x.Enum(key, loadListings...)
 			}
 		}
+	case map[string]interface{}:
+		return StepInput{}.New(NewObject(x))
 	}
 	return dest
 }
@@ -62,6 +60,8 @@ func (_ StepInput) New(i interface{}) StepInput {
 type StepInputs []StepInput
 
 // NewList constructs a list of StepInput from interface.
+// In map form, the key is the ID of the input,
+// and the value is either the source or WorkflowStepInput.
 func (_ StepInput) NewList(i interface{}) StepInputs {
 	dest := StepInputs{}
 	switch x := i.(type) {
@@ -69,14 +69,24 @@ func (_ StepInput) NewList(i interface{}) StepInputs {
 		for _, v := range x {
 			dest = append(dest, StepInput{}.New(v))
 		}
-	case map[string]interface{}:
-		for key, v := range x {
-			item := make(map[string]interface{})
-			item[key] = v
-			dest = append(dest, StepInput{}.New(item))
+	case *Object:
+		for _, key := range x.Keys {
+			v := x.Values[key]
+			var in StepInput
+			switch v.(type) {
+			case *Object:
+				in = StepInput{}.New(v)
+			case nil:
+			default:
+				in.Source = x.Strings(key)
+			}
+			if in.ID == "" {
+				in.ID = key
+			}
+			dest = append(dest, in)
 		}
-	default:
-		dest = append(dest, StepInput{}.New(x))
+	case map[string]interface{}:
+		return StepInput{}.NewList(NewObject(x))
 	}
 	return dest
 }
@@ -18,12 +18,23 @@ func (_ StepOutput) NewList(i interface{}) []StepOutput {
 	return dest
 }
 
-// New constructs a StepOutput from interface.
+// New constructs a StepOutput from interface,
+// which is either the ID or WorkflowStepOutput.
 func (_ StepOutput) New(i interface{}) StepOutput {
 	dest := StepOutput{}
 	switch x := i.(type) {
 	case string:
 		dest.ID = x
+	case *Object:
+		x.declare("WorkflowStepOutput")
+		for _, key := range x.Keys {
+			switch key {
+			case "id":
+				dest.ID = x.String(key)
+			}
+		}
+	case map[string]interface{}:
+		return StepOutput{}.New(NewObject(x))
 	}
 	return dest
 }
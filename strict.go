@@ -0,0 +1,231 @@
+package cwl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DecodeOptions represents options to decode CWL document.
+type DecodeOptions struct {
+	// Strict reports keys which are not defined in CWL specification
+	// as UnknownFields, unless they are namespaced by "$namespaces".
+	Strict bool
+}
+
+// UnknownField represents a key of an object which is not defined in CWL specification.
+type UnknownField struct {
+	Path        string   // path of the object, e.g. "inputs.inp1"
+	Record      string   // e.g. "CommandLineBinding"
+	Key         string   // e.g. "inputBindng"
+	Line        int      // 1-origin, 0 if unknown
+	Column      int      // 1-origin, 0 if unknown
+	Suggestions []string // e.g. ["inputBinding"]
+}
+
+// Error implements error interface.
+func (f *UnknownField) Error() string {
+	msg := fmt.Sprintf("%s: unknown field \"%s\" for %s", f.Path, f.Key, f.Record)
+	if f.Path == "" {
+		msg = fmt.Sprintf("unknown field \"%s\" for %s", f.Key, f.Record)
+	}
+	if len(f.Suggestions) != 0 {
+		msg += fmt.Sprintf(", did you mean \"%s\"?", strings.Join(f.Suggestions, "\" or \""))
+	}
+	if f.Line == 0 {
+		return "Strict error: " + msg
+	}
+	return fmt.Sprintf("Strict error at line %d, column %d: %s", f.Line, f.Column, msg)
+}
+
+// UnknownFields represents all the UnknownField found in a document.
+type UnknownFields []*UnknownField
+
+// Error implements error interface.
+func (fields UnknownFields) Error() string {
+	lines := []string{}
+	for _, f := range fields {
+		lines = append(lines, f.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ByPath groups UnknownFields by the path of the object they belong to.
+func (fields UnknownFields) ByPath() map[string][]string {
+	dest := map[string][]string{}
+	for _, f := range fields {
+		dest[f.Path] = append(dest[f.Path], f.Key)
+	}
+	return dest
+}
+
+// directives are Schema Salad keywords allowed in any object.
+var directives = []string{"$import", "$include", "$mixin", "$namespaces", "$schemas", "$base", "$graph"}
+
+// recordFields represents field names of CWL records.
+// Some of them are unions of records which are decoded by the same struct.
+// Records and fields added after v1.0 are listed in version.go as well,
+// so that documents of older cwlVersion can't use them.
+// @see http://www.commonwl.org/v1.0/CommandLineTool.html
+// @see http://www.commonwl.org/v1.0/Workflow.html
+// @see https://www.commonwl.org/v1.1/CommandLineTool.html
+// @see https://www.commonwl.org/v1.2/Workflow.html
+var recordFields = map[string][]string{
+	"CommandLineTool": {
+		"id", "class", "cwlVersion", "label", "doc", "inputs", "outputs", "requirements", "hints",
+		"baseCommand", "arguments", "stdin", "stdout", "stderr",
+		"successCodes", "temporaryFailCodes", "permanentFailCodes",
+	},
+	"Workflow":       {"id", "class", "cwlVersion", "label", "doc", "inputs", "outputs", "requirements", "hints", "steps"},
+	"ExpressionTool": {"id", "class", "cwlVersion", "label", "doc", "inputs", "outputs", "requirements", "hints", "expression"},
+	"Operation":      {"id", "class", "cwlVersion", "label", "doc", "inputs", "outputs", "requirements", "hints"},
+	// CommandInputParameter and InputParameter
+	"InputParameter": {"id", "label", "doc", "secondaryFiles", "streamable", "format", "inputBinding", "default", "type", "loadContents", "loadListing"},
+	// CommandOutputParameter, ExpressionToolOutputParameter and WorkflowOutputParameter
+	"OutputParameter":      {"id", "label", "doc", "secondaryFiles", "streamable", "format", "outputBinding", "outputSource", "linkMerge", "pickValue", "type"},
+	"CommandLineBinding":   {"loadContents", "position", "prefix", "separate", "itemSeparator", "valueFrom", "shellQuote"},
+	"CommandOutputBinding": {"glob", "loadContents", "outputEval", "loadListing"},
+	// Record, Enum and Array schemas
+	"Schema": {"type", "label", "doc", "name", "fields", "symbols", "items", "inputBinding", "outputBinding"},
+	// CommandInputRecordField and CommandOutputRecordField
+	"RecordField": {
+		"name", "label", "doc", "type", "inputBinding", "outputBinding",
+		"secondaryFiles", "streamable", "format", "loadContents", "loadListing",
+	},
+	"WorkflowStep":       {"id", "label", "doc", "in", "out", "run", "requirements", "hints", "scatter", "scatterMethod", "when"},
+	"WorkflowStepOutput": {"id"},
+	"WorkflowStepInput":  {"id", "label", "source", "linkMerge", "pickValue", "default", "valueFrom", "loadContents", "loadListing"},
+	"Dirent":             {"entry", "entryname", "writable"},
+	"File": {
+		"class", "location", "path", "basename", "dirname", "nameroot", "nameext",
+		"checksum", "size", "secondaryFiles", "format", "contents",
+	},
+	"Directory":                       {"class", "location", "path", "basename", "listing"},
+	"InlineJavascriptRequirement":     {"class", "expressionLib"},
+	"SchemaDefRequirement":            {"class", "types"},
+	"DockerRequirement":               {"class", "dockerPull", "dockerLoad", "dockerFile", "dockerImport", "dockerImageId", "dockerOutputDirectory"},
+	"SoftwareRequirement":             {"class", "packages"},
+	"InitialWorkDirRequirement":       {"class", "listing"},
+	"EnvVarRequirement":               {"class", "envDef"},
+	"ShellCommandRequirement":         {"class"},
+	"ResourceRequirement":             {"class", "coresMin", "coresMax", "ramMin", "ramMax", "tmpdirMin", "tmpdirMax", "outdirMin", "outdirMax"},
+	"SubworkflowFeatureRequirement":   {"class"},
+	"ScatterFeatureRequirement":       {"class"},
+	"MultipleInputFeatureRequirement": {"class"},
+	"StepInputExpressionRequirement":  {"class"},
+	"SoftwarePackage":                 {"package", "version", "specs"},
+	"EnvironmentDef":                  {"envName", "envValue"},
+	"SecondaryFileSchema":             {"pattern", "required"},
+	"LoadListingRequirement":          {"class", "loadListing"},
+	"WorkReuse":                       {"class", "enableReuse"},
+	"NetworkAccess":                   {"class", "networkAccess"},
+	"InplaceUpdateRequirement":        {"class", "inplaceUpdate"},
+	"ToolTimeLimit":                   {"class", "timelimit"},
+}
+
+// checkStrict walks the tree and lists keys which are not defined for the record of each object.
+func checkStrict(i interface{}, namespaces Namespaces) UnknownFields {
+	dest := UnknownFields{}
+	switch x := i.(type) {
+	case *Object:
+		if fields, ok := recordFields[x.record]; ok {
+			for _, key := range x.Keys {
+				if contains(fields, key) || contains(directives, key) || isExtension(key, namespaces) {
+					continue
+				}
+				f := &UnknownField{Path: x.path, Record: x.record, Key: key, Suggestions: suggest(key, fields)}
+				if pos, ok := x.positions[key]; ok {
+					f.Line, f.Column = pos.Line, pos.Column
+				}
+				dest = append(dest, f)
+			}
+		}
+		for _, key := range x.Keys {
+			dest = append(dest, checkStrict(x.Values[key], namespaces)...)
+		}
+	case []interface{}:
+		for _, v := range x {
+			dest = append(dest, checkStrict(v, namespaces)...)
+		}
+	}
+	return dest
+}
+
+// isExtension returns true if the key is an absolute IRI
+// or prefixed by a namespace declared in "$namespaces".
+func isExtension(key string, namespaces Namespaces) bool {
+	n := strings.Index(key, ":")
+	if n < 0 {
+		return false
+	}
+	if strings.HasPrefix(key[n:], "://") {
+		return true
+	}
+	prefix := key[:n]
+	for _, ns := range namespaces {
+		if _, ok := ns[prefix]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns true if the list contains the string.
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest lists candidates similar to the key, nearest first.
+func suggest(key string, candidates []string) []string {
+	type scored struct {
+		name     string
+		distance int
+	}
+	found := []scored{}
+	for _, c := range candidates {
+		d := distance(strings.ToLower(key), strings.ToLower(c))
+		if d <= 2 || d <= len(c)/3 {
+			found = append(found, scored{c, d})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].distance < found[j].distance
+	})
+	dest := []string{}
+	for _, f := range found {
+		dest = append(dest, f.name)
+	}
+	return dest
+}
+
+// distance calculates Levenshtein distance of 2 strings.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
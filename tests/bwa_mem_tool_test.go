@@ -18,7 +18,7 @@ func TestDecode_bwa_mem_tool(t *testing.T) {
 	Expect(t, root.Class).ToBe("CommandLineTool")
 	Expect(t, root.Hints).TypeOf("cwl.Hints")
 	Expect(t, root.Hints[0].Class).ToBe("ResourceRequirement")
-	Expect(t, root.Hints[0].CoresMin).ToBe(2)
+	Expect(t, root.Hints[0].CoresMin).ToBe(2.0)
 
 	Expect(t, len(root.Inputs)).ToBe(5)
 	Expect(t, root.Inputs).TypeOf("cwl.Inputs")
@@ -21,7 +21,7 @@ func TestDecode_cat5_tool(t *testing.T) {
 	Expect(t, root.Hints[0].Class).ToBe("DockerRequirement")
 	Expect(t, root.Hints[0].DockerPull).ToBe("debian:wheezy")
 	Expect(t, root.Hints[1].Class).ToBe("ex:BlibberBlubberFakeRequirement")
-	Expect(t, root.Hints[1].FakeField).ToBe("fraggleFroogle")
+	Expect(t, root.Hints[1].Extension.Raw["fakeField"]).ToBe("fraggleFroogle")
 	Expect(t, len(root.Inputs)).ToBe(1)
 	Expect(t, root.Inputs[0].ID).ToBe("file1")
 	Expect(t, root.Inputs[0].Types[0].Type).ToBe("File")
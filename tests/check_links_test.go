@@ -0,0 +1,187 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const links = `
+cwlVersion: v1.0
+class: Workflow
+requirements:
+  ScatterFeatureRequirement: {}
+  MultipleInputFeatureRequirement: {}
+  StepInputExpressionRequirement: {}
+  SubworkflowFeatureRequirement: {}
+inputs:
+  files: File[]
+  file: File
+  count: int
+outputs:
+  lines:
+    type: int[]
+    outputSource: wc/lines
+  single:
+    type: int
+    outputSource: wc/lines
+steps:
+  wc:
+    scatter: file
+    in:
+      file: files
+    out: [lines]
+    run:
+      class: CommandLineTool
+      baseCommand: wc
+      inputs:
+        file: File
+      outputs:
+        lines: int
+  wrong:
+    in:
+      file: files
+      n: file
+      m:
+        source: file
+        valueFrom: $(self.size)
+    out: []
+    run:
+      class: CommandLineTool
+      baseCommand: head
+      inputs:
+        file: File
+        n: int
+        m: int
+      outputs: []
+  merged:
+    in:
+      files:
+        source: [file, files]
+        linkMerge: merge_flattened
+      nested:
+        source: [file, file]
+    out: []
+    run:
+      class: CommandLineTool
+      baseCommand: cat
+      inputs:
+        files: File[]
+        nested: File[]
+      outputs: []
+  sub:
+    in:
+      n: count
+    out: []
+    run:
+      class: Workflow
+      inputs:
+        n: long
+      outputs: []
+      steps:
+        inner:
+          scatter: n
+          in:
+            n: n
+          out: []
+          run:
+            class: CommandLineTool
+            baseCommand: echo
+            inputs:
+              n: long
+            outputs: []
+`
+
+func TestRoot_CheckLinks(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(links))
+	Expect(t, err).ToBe(nil)
+
+	errs := root.CheckLinks()
+	Expect(t, len(errs)).ToBe(4)
+	Expect(t, errs[0].Step).ToBe("wrong")
+	Expect(t, errs[0].Port).ToBe("file")
+	Expect(t, errs[0].Source).ToBe("files")
+	Expect(t, errs[0].Error()).ToBe("steps.wrong.in.file: source files of type File[] is not assignable to File without scatter")
+	Expect(t, errs[1].Error()).ToBe("steps.wrong.in.n: source file of type File is not assignable to int")
+	Expect(t, errs[2].Workflow).ToBe("sub")
+	Expect(t, errs[2].Step).ToBe("inner")
+	Expect(t, errs[2].Error()).ToBe("steps.sub.run.steps.inner.in.n: source n of type long must be an array to scatter")
+	Expect(t, errs[3].Step).ToBe("")
+	Expect(t, errs[3].Error()).ToBe("outputs.single: source wc/lines of type int[] is not assignable to int without scatter")
+}
+
+func TestRoot_CheckLinks_pickValue(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.2
+class: Workflow
+requirements:
+  InlineJavascriptRequirement: {}
+  MultipleInputFeatureRequirement: {}
+inputs:
+  flag: boolean
+  fallback: int?
+outputs:
+  out:
+    type: int
+    outputSource: [first/out, fallback]
+    pickValue: first_non_null
+  all:
+    type: int[]
+    outputSource: [first/out, fallback]
+    pickValue: all_non_null
+  raw:
+    type: int
+    outputSource: first/out
+steps:
+  first:
+    when: $(inputs.flag)
+    in:
+      flag: flag
+    out: [out]
+    run:
+      class: ExpressionTool
+      expression: "$({out: 1})"
+      inputs:
+        flag: boolean
+      outputs:
+        out: int
+`))
+	// "raw" is reported as a ParseError of v1.2 as well
+	Expect(t, err).Not().ToBe(nil)
+	errs := root.CheckLinks()
+	Expect(t, len(errs)).ToBe(1)
+	Expect(t, errs[0].Error()).ToBe("outputs.raw: source first/out of type int? is not assignable to int")
+}
+
+func TestRoot_CheckLinks_unknown_run(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs:
+  out: File
+  n: int
+outputs:
+  count:
+    type: int
+    outputSource: step1/out
+  wrong:
+    type: int
+    outputSource: "#main/out"
+steps:
+  step1:
+    in:
+      n: n
+    out: [out]
+    run: other.cwl
+`))
+	Expect(t, err).ToBe(nil)
+	// "step1/out" is not checked even if the workflow has an input of the same name.
+	errs := root.CheckLinks()
+	Expect(t, len(errs)).ToBe(1)
+	Expect(t, errs[0].Error()).ToBe("outputs.wrong: source #main/out of type File is not assignable to int")
+}
@@ -0,0 +1,90 @@
+package cwlgotest
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const bindingTool = `
+cwlVersion: v1.1
+class: CommandLineTool
+baseCommand: echo
+arguments:
+  - valueFrom: $(runtime.outdir)
+    position: $(inputs.n)
+inputs:
+  n: int
+  joined:
+    type: string
+    inputBinding:
+      prefix: --joined=
+      separate: false
+  plain:
+    type: string
+    inputBinding: {}
+outputs:
+  out:
+    type: string
+    outputBinding:
+      glob: [out.txt, $(inputs.joined)]
+      loadContents: true
+      outputEval: $(self[0].contents)
+`
+
+func TestDecode_CommandLineBinding(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(bindingTool))
+	Expect(t, err).ToBe(nil)
+
+	Expect(t, root.Inputs[1].Binding.Separate).ToBe(false)
+	Expect(t, root.Inputs[1].Binding.Prefix).ToBe("--joined=")
+	Expect(t, *root.Inputs[2].Binding).ToBe(cwl.CommandLineBinding{Separate: true, ShellQuote: true})
+
+	Expect(t, root.Arguments[0].Binding.Position).ToBe(0)
+	Expect(t, root.Arguments[0].Binding.PositionExpression).ToBe("$(inputs.n)")
+	Expect(t, root.Arguments[0].Binding.Expressions()).ToBe([]string{"$(inputs.n)", "$(runtime.outdir)"})
+}
+
+func TestDecode_CommandOutputBinding(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(bindingTool))
+	Expect(t, err).ToBe(nil)
+
+	binding := root.Outputs[0].Binding
+	Expect(t, binding.Glob).ToBe([]string{"out.txt", "$(inputs.joined)"})
+	Expect(t, binding.LoadContents).ToBe(true)
+	Expect(t, binding.Eval).ToBe("$(self[0].contents)")
+	Expect(t, binding.Expressions()).ToBe([]string{"$(inputs.joined)", "$(self[0].contents)"})
+
+	outputs := cwl.Outputs{{ID: "b", Binding: binding}, {ID: "a", Binding: binding}}
+	Expect(t, outputs.Less(0, 1)).ToBe(false)
+	Expect(t, outputs.Less(1, 0)).ToBe(false)
+	sort.Stable(outputs)
+	Expect(t, outputs[0].ID).ToBe("b")
+
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader(strings.Replace(bindingTool, "$(self[0].contents)", "self[0].contents", 1)))
+	Expect(t, err.Error()).ToBe(`Parse error at line 24, column 19: outputs.out.outputBinding.outputEval: "outputEval" must be an expression`)
+}
+
+func TestDecode_binding_strict(t *testing.T) {
+	// "prefix" is a field of CommandLineBinding, not CommandOutputBinding.
+	root := cwl.NewCWL()
+	err := root.DecodeWithOptions(strings.NewReader(strings.Replace(bindingTool, "loadContents: true", "prefix: -o", 1)), cwl.DecodeOptions{Strict: true})
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, err.(cwl.UnknownFields)[0].Record).ToBe("CommandOutputBinding")
+	Expect(t, err.(cwl.UnknownFields)[0].Key).ToBe("prefix")
+}
+
+func TestLoadContents(t *testing.T) {
+	large := make([]byte, cwl.LoadContentsLimit+1)
+	contents, err := cwl.LoadContents("v1.1", large)
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(contents)).ToBe(cwl.LoadContentsLimit)
+	_, err = cwl.LoadContents("v1.2", large)
+	Expect(t, err).Not().ToBe(nil)
+}
@@ -0,0 +1,118 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const conditionalWorkflow = `
+cwlVersion: v1.2
+class: Workflow
+requirements:
+  InlineJavascriptRequirement: {}
+inputs:
+  val: int
+outputs:
+  out1:
+    type: string
+    outputSource: [step1/out, step2/out]
+    pickValue: first_non_null
+  out2:
+    type: string[]
+    outputSource: [step1/out, step2/out]
+    pickValue: all_non_null
+  out3:
+    type: string?
+    outputSource: step1/out
+steps:
+  step1:
+    run: foo.cwl
+    when: $(inputs.val < 1)
+    in:
+      in1: val
+      a_new_var:
+        source: val
+        pickValue: the_only_non_null
+    out: [out]
+  step2:
+    run: foo.cwl
+    when: $(inputs.val >= 1)
+    in: {in1: val}
+    out: [out]
+`
+
+func TestDecode_conditional(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(conditionalWorkflow))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Steps[0].When).ToBe("$(inputs.val < 1)")
+	Expect(t, root.Steps[0].Conditional()).ToBe(true)
+	Expect(t, root.Steps[0].In[1].PickValue).ToBe("the_only_non_null")
+	Expect(t, root.Outputs[0].PickValue).ToBe("first_non_null")
+	Expect(t, root.Outputs[1].PickValue).ToBe("all_non_null")
+}
+
+func TestDecode_conditional_errors(t *testing.T) {
+	doc := strings.Replace(conditionalWorkflow, "type: string?", "type: string", 1)
+	doc = strings.Replace(doc, "type: string[]", "type: string", 1)
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(doc))
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, err.Error()).ToBe(strings.Join([]string{
+		`Parse error at line 16, column 16: outputs.out2.pickValue: output with pickValue "all_non_null" must be an array`,
+		`Parse error at line 19, column 19: outputs.out3.outputSource: output from conditional step "step1" must be nullable or use pickValue`,
+	}, "\n"))
+
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader(strings.Replace(conditionalWorkflow, "first_non_null", "first", 1)))
+	Expect(t, err).Not().ToBe(nil)
+
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader(strings.Replace(conditionalWorkflow, "$(inputs.val < 1)", "always", 1)))
+	Expect(t, err.Error()).ToBe(`Parse error at line 23, column 11: steps.step1.when: "when" must be an expression`)
+}
+
+func TestDecode_v10_rejects_v12(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(strings.Replace(conditionalWorkflow, "v1.2", "v1.1", 1)))
+	Expect(t, err).Not().ToBe(nil)
+	errs := err.(cwl.ParseErrors)
+	Expect(t, len(errs)).ToBe(5)
+	Expect(t, errs[0].Message).ToBe(`field "pickValue" of OutputParameter requires cwlVersion v1.2 or later`)
+	Expect(t, errs[2].Message).ToBe(`field "when" of WorkflowStep requires cwlVersion v1.2 or later`)
+
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader("cwlVersion: v1.0\nclass: Operation\ninputs: []\noutputs: []\n"))
+	Expect(t, err.Error()).ToBe("Parse error: Operation requires cwlVersion v1.2 or later")
+}
+
+func TestDecode_operation(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.DecodeWithOptions(strings.NewReader(`
+cwlVersion: v1.2
+class: Operation
+inputs:
+  reads: File
+outputs:
+  aligned: File
+`), cwl.DecodeOptions{Strict: true})
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Class).ToBe("Operation")
+	Expect(t, root.Inputs[0].ID).ToBe("reads")
+}
+
+func TestSecondaryFile_optional(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader("cwlVersion: v1.1\nclass: CommandLineTool\ninputs:\n  bam: {type: File, secondaryFiles: [\".bai?\"]}\noutputs: []\n"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Inputs[0].SecondaryFiles[0]).ToBe(cwl.SecondaryFile{Entry: ".bai", Required: false})
+
+	// Until v1.0, "?" is a part of the pattern.
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader("cwlVersion: v1.0\nclass: CommandLineTool\ninputs:\n  bam: {type: File, secondaryFiles: [\".bai?\"]}\noutputs: []\n"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Inputs[0].SecondaryFiles[0]).ToBe(cwl.SecondaryFile{Entry: ".bai?", Required: true})
+}
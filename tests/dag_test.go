@@ -0,0 +1,130 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const dag = `
+cwlVersion: v1.0
+class: Workflow
+requirements:
+  SubworkflowFeatureRequirement: {}
+inputs:
+  file: File
+outputs:
+  summary:
+    type: File
+    outputSource: report/out
+steps:
+  report:
+    in:
+      lines: count/lines
+      words: sub/words
+    out: [out]
+    run: report.cwl
+  count:
+    in:
+      file: file
+    out: [lines]
+    run: wc.cwl
+  sub:
+    in:
+      file: file
+    out: [words]
+    run:
+      class: Workflow
+      inputs:
+        file: File
+      outputs:
+        words:
+          type: int
+          outputSource: split/words
+      steps:
+        split:
+          in:
+            file: file
+          out: [words]
+          run: split.cwl
+  log:
+    in: []
+    out: []
+    run: log.cwl
+`
+
+func ids(nodes []*cwl.Node) []string {
+	dest := []string{}
+	for _, node := range nodes {
+		dest = append(dest, node.ID)
+	}
+	return dest
+}
+
+func TestNewDAG(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(dag))
+	Expect(t, err).ToBe(nil)
+
+	g, err := cwl.NewDAG(root)
+	Expect(t, err).ToBe(nil)
+	Expect(t, ids(g.Nodes)).ToBe([]string{"file", "report", "count", "sub/file", "sub/split", "sub/words", "log", "summary"})
+	Expect(t, g.Nodes[4].Kind).ToBe(cwl.NodeStep)
+	Expect(t, g.Nodes[4].Workflow).ToBe("sub")
+	Expect(t, g.Nodes[4].Name()).ToBe("split")
+	Expect(t, g.Nodes[7].Kind).ToBe(cwl.NodeOutput)
+	Expect(t, len(g.Edges)).ToBe(7)
+	Expect(t, g.Edges[0]).ToBe(cwl.Edge{From: "sub/file", To: "sub/split", ToPort: "file"})
+	Expect(t, g.Edges[1]).ToBe(cwl.Edge{From: "sub/split", FromPort: "words", To: "sub/words"})
+	Expect(t, g.Edges[2]).ToBe(cwl.Edge{From: "count", FromPort: "lines", To: "report", ToPort: "lines"})
+	Expect(t, g.Edges[3]).ToBe(cwl.Edge{From: "sub/words", To: "report", ToPort: "words"})
+	Expect(t, g.Edges[5]).ToBe(cwl.Edge{From: "file", To: "sub/file"})
+	Expect(t, g.Edges[6]).ToBe(cwl.Edge{From: "report", FromPort: "out", To: "summary"})
+
+	Expect(t, ids(g.Steps())).ToBe([]string{"report", "count", "sub/split", "log"})
+	Expect(t, ids(g.Upstream("report"))).ToBe([]string{"count", "sub/split"})
+	Expect(t, ids(g.Downstream("sub/split"))).ToBe([]string{"report"})
+	Expect(t, ids(g.Roots())).ToBe([]string{"count", "sub/split", "log"})
+	Expect(t, ids(g.Leaves())).ToBe([]string{"report", "log"})
+
+	levels, err := g.Levels()
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(levels)).ToBe(2)
+	Expect(t, ids(levels[0])).ToBe([]string{"count", "sub/split", "log"})
+	Expect(t, ids(levels[1])).ToBe([]string{"report"})
+
+	order, err := g.TopologicalOrder()
+	Expect(t, err).ToBe(nil)
+	Expect(t, ids(order)).ToBe([]string{"count", "sub/split", "log", "report"})
+}
+
+func TestNewDAG_cycle(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs: []
+outputs: []
+steps:
+  a:
+    in: {x: b/out}
+    out: [out]
+    run: a.cwl
+  b:
+    in: {x: a/out}
+    out: [out]
+    run: b.cwl
+`))
+	Expect(t, err).ToBe(nil)
+	g, err := cwl.NewDAG(root)
+	Expect(t, err).ToBe(nil)
+	_, err = g.TopologicalOrder()
+	Expect(t, err.Error()).ToBe("steps form a cycle: a, b")
+
+	tool := cwl.NewCWL()
+	tool.Class = "CommandLineTool"
+	_, err = cwl.NewDAG(tool)
+	Expect(t, err).Not().ToBe(nil)
+}
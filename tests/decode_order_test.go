@@ -0,0 +1,120 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const orderedDocument = `
+cwlVersion: v1.0
+class: Workflow
+hints:
+  ResourceRequirement:
+    coresMin: 2
+  DockerRequirement:
+    dockerPull: debian:8
+requirements:
+  SubworkflowFeatureRequirement: {}
+  EnvVarRequirement:
+    envDef:
+      ZZZ: last
+      AAA: first
+inputs:
+  zeta: string
+  alpha: int
+  mu:
+    type:
+      type: record
+      fields:
+        second: string
+        first: int
+outputs:
+  out_z:
+    type: File
+    outputSource: step_z/out
+  out_a:
+    type: File
+    outputSource: step_a/out
+steps:
+  step_z:
+    run: z.cwl
+    in:
+      y: zeta
+      x: alpha
+    out: [out]
+  step_a:
+    run: a.cwl
+    in: {}
+    out: [out]
+`
+
+func TestDecode_order(t *testing.T) {
+	for n := 0; n < 10; n++ {
+		root := cwl.NewCWL()
+		err := root.Decode(strings.NewReader(orderedDocument))
+		Expect(t, err).ToBe(nil)
+		Expect(t, root.Hints[0].Class).ToBe("ResourceRequirement")
+		Expect(t, root.Hints[1].Class).ToBe("DockerRequirement")
+		Expect(t, root.Requirements[0].Class).ToBe("SubworkflowFeatureRequirement")
+		Expect(t, root.Requirements[1].EnvDef[0].Name).ToBe("ZZZ")
+		Expect(t, root.Requirements[1].EnvDef[1].Name).ToBe("AAA")
+		Expect(t, root.Inputs[0].ID).ToBe("zeta")
+		Expect(t, root.Inputs[1].ID).ToBe("alpha")
+		Expect(t, root.Inputs[2].ID).ToBe("mu")
+		Expect(t, root.Inputs[2].Types[0].Fields[0].Types[0].Type).ToBe("string")
+		Expect(t, root.Inputs[2].Types[0].Fields[1].Types[0].Type).ToBe("int")
+		Expect(t, root.Outputs[0].ID).ToBe("out_z")
+		Expect(t, root.Outputs[1].ID).ToBe("out_a")
+		Expect(t, root.Steps[0].ID).ToBe("step_z")
+		Expect(t, root.Steps[0].In[0].ID).ToBe("y")
+		Expect(t, root.Steps[0].In[1].ID).ToBe("x")
+		Expect(t, root.Steps[1].ID).ToBe("step_a")
+	}
+}
+
+func TestUnmarshalJSON_order(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.UnmarshalJSON([]byte(`{"class": "CommandLineTool", "inputs": {"b": "string", "a": "int"}}`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Inputs[0].ID).ToBe("b")
+	Expect(t, root.Inputs[1].ID).ToBe("a")
+}
+
+func TestNew_map(t *testing.T) {
+	input := cwl.Input{}.New(map[string]interface{}{
+		"id":           "reads",
+		"type":         "File",
+		"inputBinding": map[string]interface{}{"position": float64(2)},
+	})
+	Expect(t, input.ID).ToBe("reads")
+	Expect(t, input.Types[0].Type).ToBe("File")
+	Expect(t, input.Binding.Position).ToBe(2)
+
+	output := cwl.Output{}.New(map[string]interface{}{"id": "out", "type": "File"})
+	Expect(t, output.ID).ToBe("out")
+
+	req := cwl.Requirement{}.New(map[string]interface{}{"class": "DockerRequirement", "dockerPull": "debian:8"})
+	Expect(t, req.Class).ToBe("DockerRequirement")
+	Expect(t, req.DockerPull).ToBe("debian:8")
+
+	hints := cwl.Hints{}.New([]interface{}{
+		map[string]interface{}{"class": "EnvVarRequirement", "envDef": []interface{}{
+			map[string]interface{}{"envName": "A", "envValue": "a"},
+		}},
+	})
+	Expect(t, hints[0].Envs[0].Name).ToBe("A")
+
+	step := cwl.Step{}.New(map[string]interface{}{
+		"id":  "s",
+		"run": "tool.cwl",
+		"in":  map[string]interface{}{"x": "alpha"},
+		"out": []interface{}{"out"},
+	})
+	Expect(t, step.ID).ToBe("s")
+	Expect(t, step.Run.Value).ToBe("tool.cwl")
+	Expect(t, step.In[0].Source).ToBe([]string{"alpha"})
+	Expect(t, step.Out[0].ID).ToBe("out")
+}
@@ -16,7 +16,7 @@ func TestDecode_dir5(t *testing.T) {
 
 	Expect(t, root.Requirements[0].Class).ToBe("ShellCommandRequirement")
 	Expect(t, root.Requirements[1].Class).ToBe("InitialWorkDirRequirement")
-	Expect(t, root.Requirements[1].Listing[0].Location).ToBe("$(inputs.indir.listing)")
+	Expect(t, root.Requirements[1].Listing[0].Expression).ToBe("$(inputs.indir.listing)")
 	Expect(t, root.Inputs[0].ID).ToBe("indir")
 	Expect(t, root.Inputs[0].Types[0].Type).ToBe("Directory")
 	Expect(t, root.Outputs[0].ID).ToBe("outlist")
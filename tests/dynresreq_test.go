@@ -15,8 +15,8 @@ func TestDecode_dynresreq(t *testing.T) {
 	Expect(t, root.Version).ToBe("v1.0")
 	Expect(t, root.Class).ToBe("CommandLineTool")
 	Expect(t, root.Requirements[0].Class).ToBe("ResourceRequirement")
-	// TODO check CoresMin and CoresMax
-	//Expect(t, root.Requirements[0].CoreMin).ToBe("$(inputs.special_file.size)")
+	Expect(t, root.Requirements[0].CoresMinExpression).ToBe("$(inputs.special_file.size)")
+	Expect(t, root.Requirements[0].CoresMaxExpression).ToBe("$(inputs.special_file.size)")
 	Expect(t, root.Inputs[0].ID).ToBe("special_file")
 	Expect(t, root.Inputs[0].Types[0].Type).ToBe("File")
 	Expect(t, root.Outputs[0].ID).ToBe("output")
@@ -0,0 +1,118 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const effective = `
+cwlVersion: v1.0
+class: Workflow
+id: main
+requirements:
+  ResourceRequirement:
+    coresMin: 2
+  SubworkflowFeatureRequirement: {}
+hints:
+  DockerRequirement:
+    dockerPull: debian:8
+inputs: []
+outputs: []
+steps:
+  tool:
+    requirements:
+      ResourceRequirement:
+        coresMin: 4
+    in: []
+    out: []
+    run:
+      class: CommandLineTool
+      id: echo
+      baseCommand: echo
+      hints:
+        ResourceRequirement:
+          coresMin: 8
+        DockerRequirement:
+          dockerPull: alpine
+      inputs: []
+      outputs: []
+  sub:
+    in: []
+    out: []
+    run:
+      class: Workflow
+      inputs: []
+      outputs: []
+      steps:
+        inner:
+          hints:
+            EnvVarRequirement:
+              envDef:
+                LANG: C
+          in: []
+          out: []
+          run:
+            class: CommandLineTool
+            baseCommand: echo
+            requirements:
+              DockerRequirement:
+                dockerPull: ubuntu
+            inputs: []
+            outputs: []
+`
+
+func TestEffectiveRequirements(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(effective))
+	Expect(t, err).ToBe(nil)
+
+	reqs, err := cwl.EffectiveRequirements(root, "tool")
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(reqs)).ToBe(3)
+	// Requirements of the step override the workflow, and hints of the tool don't
+	Expect(t, reqs[0].Class).ToBe("ResourceRequirement")
+	Expect(t, reqs[0].CoresMin).ToBe(4.0)
+	Expect(t, reqs[0].Soft).ToBe(false)
+	Expect(t, reqs[0].Level).ToBe(cwl.LevelStep)
+	Expect(t, reqs[0].Source).ToBe("tool")
+	Expect(t, reqs[1].Class).ToBe("SubworkflowFeatureRequirement")
+	Expect(t, reqs[1].Level).ToBe(cwl.LevelWorkflow)
+	Expect(t, reqs[1].Source).ToBe("main")
+	// Hints of the tool override the workflow
+	Expect(t, reqs[2].Class).ToBe("DockerRequirement")
+	Expect(t, reqs[2].DockerPull).ToBe("alpine")
+	Expect(t, reqs[2].Soft).ToBe(true)
+	Expect(t, reqs[2].Level).ToBe(cwl.LevelProcess)
+	Expect(t, reqs[2].Source).ToBe("echo")
+}
+
+func TestEffectiveRequirements_nested(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(effective))
+	Expect(t, err).ToBe(nil)
+
+	reqs, err := cwl.EffectiveRequirements(root, "sub/inner")
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(reqs)).ToBe(4)
+	Expect(t, reqs[0].Class).ToBe("ResourceRequirement")
+	Expect(t, reqs[0].CoresMin).ToBe(2.0)
+	Expect(t, reqs[1].Class).ToBe("SubworkflowFeatureRequirement")
+	// A requirement of the innermost tool overrides a hint of the workflow
+	Expect(t, reqs[2].Class).ToBe("DockerRequirement")
+	Expect(t, reqs[2].DockerPull).ToBe("ubuntu")
+	Expect(t, reqs[2].Soft).ToBe(false)
+	Expect(t, reqs[2].Level).ToBe(cwl.LevelProcess)
+	Expect(t, reqs[2].Source).ToBe("inner/run")
+	Expect(t, reqs[3].Class).ToBe("EnvVarRequirement")
+	Expect(t, reqs[3].EnvDef[0].Name).ToBe("LANG")
+	Expect(t, reqs[3].Level).ToBe(cwl.LevelStep)
+	Expect(t, reqs[3].Source).ToBe("inner")
+
+	_, err = cwl.EffectiveRequirements(root, "inner")
+	Expect(t, err).Not().ToBe(nil)
+	_, err = cwl.EffectiveRequirements(root, "nothing")
+	Expect(t, err.Error()).ToBe("step not found: nothing")
+}
@@ -0,0 +1,67 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+func TestRoot_ClassifyExit(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: grep
+successCodes: [1]
+temporaryFailCodes: [75, 0]
+permanentFailCodes: [2]
+inputs: []
+outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.SuccessCodes).ToBe([]int{1})
+	Expect(t, root.TemporaryFailCodes).ToBe([]int{75, 0})
+	Expect(t, root.PermanentFailCodes).ToBe([]int{2})
+	Expect(t, root.ClassifyExit(1)).ToBe(cwl.StatusSuccess)
+	Expect(t, root.ClassifyExit(75)).ToBe(cwl.StatusTemporaryFail)
+	Expect(t, root.ClassifyExit(0)).ToBe(cwl.StatusTemporaryFail)
+	Expect(t, root.ClassifyExit(2)).ToBe(cwl.StatusPermanentFail)
+	Expect(t, root.ClassifyExit(3)).ToBe(cwl.StatusPermanentFail)
+}
+
+func TestRoot_ClassifyExit_default(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: echo
+inputs: []
+outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.ClassifyExit(0)).ToBe(cwl.StatusSuccess)
+	Expect(t, root.ClassifyExit(1)).ToBe(cwl.StatusPermanentFail)
+	Expect(t, root.ClassifyExit(-1)).ToBe(cwl.StatusPermanentFail)
+}
+
+func TestDecode_exit_codes_invalid(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: echo
+successCodes: [0, one]
+temporaryFailCodes: 75
+inputs: []
+outputs: []
+`))
+	Expect(t, err).Not().ToBe(nil)
+	errs := err.(cwl.ParseErrors)
+	Expect(t, len(errs)).ToBe(2)
+	Expect(t, errs[0].Path).ToBe("successCodes[1]")
+	Expect(t, errs[0].Expected).ToBe("int")
+	Expect(t, errs[1].Path).ToBe("temporaryFailCodes")
+	Expect(t, errs[1].Expected).ToBe("sequence of int")
+}
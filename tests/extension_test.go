@@ -0,0 +1,75 @@
+package cwlgotest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+type loadListingRequirement struct {
+	LoadListing string
+}
+
+func decodeLoadListing(fields map[string]interface{}) (interface{}, error) {
+	listing, ok := fields["loadListing"].(string)
+	if !ok {
+		return nil, fmt.Errorf("loadListing must be string")
+	}
+	return &loadListingRequirement{LoadListing: listing}, nil
+}
+
+const extendedDocument = `
+cwlVersion: v1.0
+class: CommandLineTool
+requirements:
+  cwltool:LoadListingRequirement:
+    loadListing: shallow_listing
+hints:
+  - class: arv:RuntimeConstraints
+    keep_cache: 512
+    outputDirType: keep_output_dir
+  - class: DockerRequirement
+    dockerPull: debian:8
+inputs: []
+outputs: []
+$namespaces:
+  cwltool: http://commonwl.org/cwltool#
+  arv: http://arvados.org/cwl#
+`
+
+func TestRegisterExtension(t *testing.T) {
+	cwl.RegisterExtension("http://commonwl.org/cwltool#LoadListingRequirement", decodeLoadListing)
+	defer cwl.RegisterExtension("http://commonwl.org/cwltool#LoadListingRequirement", nil)
+
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(extendedDocument))
+	Expect(t, err).ToBe(nil)
+
+	ext := root.Requirements[0].Extension
+	Expect(t, ext.IRI).ToBe("http://commonwl.org/cwltool#LoadListingRequirement")
+	Expect(t, ext.Value.(*loadListingRequirement).LoadListing).ToBe("shallow_listing")
+
+	// Unknown extension is preserved as raw map.
+	ext = root.Hints[0].Extension
+	Expect(t, root.Hints[0].Class).ToBe("arv:RuntimeConstraints")
+	Expect(t, ext.IRI).ToBe("http://arvados.org/cwl#RuntimeConstraints")
+	Expect(t, ext.Value).ToBe(nil)
+	Expect(t, ext.Raw["keep_cache"]).ToBe(float64(512))
+	Expect(t, ext.Raw["outputDirType"]).ToBe("keep_output_dir")
+
+	// Classes defined in CWL specification are not extensions.
+	Expect(t, root.Hints[1].Extension).ToBe((*cwl.Extension)(nil))
+}
+
+func TestRegisterExtension_error(t *testing.T) {
+	cwl.RegisterExtension("http://commonwl.org/cwltool#LoadListingRequirement", decodeLoadListing)
+	defer cwl.RegisterExtension("http://commonwl.org/cwltool#LoadListingRequirement", nil)
+
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(strings.Replace(extendedDocument, "loadListing: shallow_listing", "loadListing: 1", 1)))
+	Expect(t, err).TypeOf("cwl.ParseErrors")
+	Expect(t, err.Error()).ToBe("Parse error: requirements.cwltool:LoadListingRequirement: loadListing must be string")
+}
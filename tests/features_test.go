@@ -0,0 +1,150 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+func TestUsesJavascript(t *testing.T) {
+	Expect(t, cwl.UsesJavascript("$(inputs.file.basename)")).ToBe(false)
+	Expect(t, cwl.UsesJavascript("out_$(inputs['sample name']).txt")).ToBe(false)
+	Expect(t, cwl.UsesJavascript("$(self[0].contents)")).ToBe(false)
+	Expect(t, cwl.UsesJavascript("$(inputs.n + 1)")).ToBe(true)
+	Expect(t, cwl.UsesJavascript("$(inputs.files.map(function(f) { return f.path; }))")).ToBe(true)
+	Expect(t, cwl.UsesJavascript("${ return 1; }")).ToBe(true)
+	Expect(t, cwl.UsesJavascript(`\${ not an expression }`)).ToBe(false)
+	Expect(t, cwl.UsesJavascript("plain text")).ToBe(false)
+}
+
+func TestValidate_features(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+requirements:
+  MultipleInputFeatureRequirement: {}
+  SubworkflowFeatureRequirement: {}
+inputs:
+  files: File[]
+  n: int
+outputs: []
+steps:
+  count:
+    scatter: file
+    in:
+      file: files
+      n:
+        source: n
+        valueFrom: $(self + 1)
+    out: [lines]
+    run:
+      class: CommandLineTool
+      baseCommand: wc
+      arguments: [$(inputs.n * 2)]
+      inputs:
+        file: File
+        n: int
+      outputs:
+        lines:
+          type: int
+          outputBinding:
+            glob: $(inputs.file.nameroot)
+            loadContents: true
+            outputEval: $(parseInt(self[0].contents))
+  echo:
+    requirements:
+      ScatterFeatureRequirement: {}
+      InlineJavascriptRequirement: {}
+    in:
+      message: n
+    out: [out]
+    run:
+      class: CommandLineTool
+      baseCommand: echo
+      stdout: $(inputs.message).txt
+      inputs:
+        message: int
+      outputs:
+        out: stdout
+`))
+	Expect(t, err).ToBe(nil)
+
+	messages := []string{}
+	for _, d := range cwl.Validate(root) {
+		messages = append(messages, d.String())
+	}
+	Expect(t, messages).ToBe([]string{
+		`error: steps.count: expression "$(self + 1)" requires InlineJavascriptRequirement`,
+		`error: steps.count: scatter requires ScatterFeatureRequirement`,
+		`error: steps.count: valueFrom requires StepInputExpressionRequirement`,
+		`error: steps.count.run: expression "$(inputs.n * 2)" requires InlineJavascriptRequirement`,
+		`warning: steps.echo.requirements: InlineJavascriptRequirement is declared but not used`,
+		`warning: steps.echo.requirements: ScatterFeatureRequirement is declared but not used`,
+		`warning: requirements: SubworkflowFeatureRequirement is declared but not used`,
+		`warning: requirements: MultipleInputFeatureRequirement is declared but not used`,
+	})
+}
+
+func TestValidate_features_inherited(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+requirements:
+  InlineJavascriptRequirement: {}
+  SubworkflowFeatureRequirement: {}
+inputs:
+  a: int
+  b: int
+outputs:
+  sum:
+    type: int[]
+    outputSource: [sub/out, sub/out]
+steps:
+  sub:
+    requirements:
+      MultipleInputFeatureRequirement: {}
+    in:
+      a: a
+      b: b
+    out: [out]
+    run:
+      class: Workflow
+      inputs:
+        a: int
+        b: int
+      outputs:
+        out:
+          type: int
+          outputSource: add/out
+      steps:
+        add:
+          in:
+            values: [a, b]
+          out: [out]
+          run:
+            class: ExpressionTool
+            expression: "$({out: inputs.values[0] + inputs.values[1]})"
+            inputs:
+              values: int[]
+            outputs:
+              out: int
+        unknown:
+          in: []
+          out: []
+          run: unknown.cwl
+`))
+	Expect(t, err).ToBe(nil)
+
+	messages := []string{}
+	for _, d := range cwl.Validate(root) {
+		messages = append(messages, d.String())
+	}
+	// Unnecessary declarations are not reported since "unknown.cwl" is not loaded
+	Expect(t, messages).ToBe([]string{
+		`error: outputs.sum: multiple sources require MultipleInputFeatureRequirement`,
+	})
+}
@@ -0,0 +1,100 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const packed = `
+cwlVersion: v1.0
+$graph:
+  - id: "#revtool"
+    class: CommandLineTool
+    baseCommand: rev
+    inputs: {input: File}
+    outputs: {output: stdout}
+  - id: "#sorttool"
+    class: CommandLineTool
+    baseCommand: sort
+    inputs: {input: File}
+    outputs: {output: stdout}
+  - id: "#main"
+    class: Workflow
+    inputs: {input: File}
+    outputs: {output: {type: File, outputSource: sorted/output}}
+    steps:
+      rev:
+        run: "#revtool"
+        in: {input: input}
+        out: [output]
+      sorted:
+        run: "#sorttool"
+        in: {input: rev/output}
+        out: [output]
+`
+
+func decodeString(t *testing.T, doc string) *cwl.Root {
+	root := cwl.NewCWL()
+	Expect(t, root.Decode(strings.NewReader(doc))).ToBe(nil)
+	return root
+}
+
+func TestRoot_Entry(t *testing.T) {
+	root := decodeString(t, packed)
+
+	entry, err := root.Entry("")
+	Expect(t, err).ToBe(nil)
+	Expect(t, entry.ID).ToBe("#main")
+	Expect(t, entry.Version).ToBe("v1.0")
+	Expect(t, entry.Steps[0].Run.Workflow.ID).ToBe("#revtool")
+	Expect(t, entry.Steps[0].Run.Workflow.BaseCommands[0]).ToBe("rev")
+	Expect(t, entry.Steps[1].Run.Workflow.ID).ToBe("#sorttool")
+
+	tool, err := root.Entry("#sorttool")
+	Expect(t, err).ToBe(nil)
+	Expect(t, tool.Class).ToBe("CommandLineTool")
+	Expect(t, tool.Version).ToBe("v1.0")
+
+	_, err = root.Entry("nothing")
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestRoot_Entry_default(t *testing.T) {
+	// The only Workflow is the entry, even if it's not "#main".
+	root := decodeString(t, strings.Replace(packed, `"#main"`, `"#wf"`, 1))
+	entry, err := root.Entry("")
+	Expect(t, err).ToBe(nil)
+	Expect(t, entry.ID).ToBe("#wf")
+
+	// Ambiguous without "#main" nor Workflow.
+	root = decodeString(t, strings.Split(packed, `  - id: "#main"`)[0])
+	_, err = root.Entry("")
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, strings.Contains(err.Error(), "#revtool, #sorttool")).ToBe(true)
+
+	// Document without "$graph" is the entry itself.
+	root = decodeString(t, "cwlVersion: v1.0\nclass: CommandLineTool\nbaseCommand: echo\ninputs: []\noutputs: []\n")
+	entry, err = root.Entry("")
+	Expect(t, err).ToBe(nil)
+	Expect(t, entry).ToBe(root)
+}
+
+func TestRoot_Entry_unresolved_run(t *testing.T) {
+	root := decodeString(t, strings.Replace(packed, `run: "#sorttool"`, `run: "#missing"`, 1))
+	_, err := root.Entry("")
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, err.Error()).ToBe(`run "#missing" of step sorted is not found in $graph`)
+}
+
+func TestRoot_Entry_recursive_run(t *testing.T) {
+	root := decodeString(t, strings.Replace(packed, `run: "#sorttool"`, `run: "#main"`, 1))
+	entry, err := root.Entry("")
+	Expect(t, err).ToBe(nil)
+	Expect(t, entry.Steps[1].Run.Workflow).ToBe(root.Graphs[2])
+	// Linking again doesn't follow the recursion.
+	_, err = root.Entry("")
+	Expect(t, err).ToBe(nil)
+}
@@ -0,0 +1,52 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+func TestDecode_hints_typed(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: echo
+requirements:
+  ResourceRequirement:
+    coresMin: 4
+hints:
+  DockerRequirement:
+    dockerPull: debian:8
+    dockerImageId: debian:8-slim
+  ResourceRequirement:
+    coresMin: 2
+    ramMin: 2048
+  EnvVarRequirement:
+    envDef:
+      LANG: C
+inputs: []
+outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+
+	Expect(t, root.Hints[0].DockerImageID).ToBe("debian:8-slim")
+	Expect(t, root.Hints[0].Soft).ToBe(true)
+	Expect(t, root.Hints[1].RAMMin).ToBe(2048)
+	Expect(t, root.Hints[2].Envs[0].Name).ToBe("LANG")
+	Expect(t, root.Hints[2].EnvDef[0].Value).ToBe("C")
+	Expect(t, root.Requirements[0].Soft).ToBe(false)
+
+	docker := root.Lookup("DockerRequirement")
+	Expect(t, docker.DockerPull).ToBe("debian:8")
+	Expect(t, docker.Soft).ToBe(true)
+
+	// Requirements take precedence over hints.
+	resource := root.Lookup("ResourceRequirement")
+	Expect(t, resource.CoresMin).ToBe(4.0)
+	Expect(t, resource.Soft).ToBe(false)
+
+	Expect(t, root.Lookup("ShellCommandRequirement") == nil).ToBe(true)
+}
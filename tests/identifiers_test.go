@@ -0,0 +1,119 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const packedDocument = `
+cwlVersion: v1.0
+$graph:
+  - id: echo
+    class: CommandLineTool
+    requirements:
+      - class: SchemaDefRequirement
+        types:
+          - name: Stage
+            type: record
+            fields: []
+    inputs:
+      - id: "#echo/text"
+        type: "#Stage[]"
+    outputs:
+      echo_out:
+        type: stdout
+    baseCommand: echo
+  - id: "#main"
+    class: Workflow
+    inputs:
+      inp1: string
+    outputs:
+      out:
+        type: File
+        outputSource: step1/echo_out
+    steps:
+      step1:
+        run: "#echo"
+        scatter: text
+        in:
+          text: inp1
+        out: [echo_out]
+      step2:
+        run: tools/rev.cwl
+        in:
+          input:
+            source: [step1/echo_out, inp1]
+        out: [output]
+`
+
+func TestRoot_ResolveIdentifiers(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(packedDocument))
+	Expect(t, err).ToBe(nil)
+	root.ResolveIdentifiers("file:///work/packed.cwl")
+
+	echo, main := root.Graphs[0], root.Graphs[1]
+	Expect(t, echo.ID).ToBe("file:///work/packed.cwl#echo")
+	Expect(t, echo.Inputs[0].ID).ToBe("file:///work/packed.cwl#echo/text")
+	Expect(t, echo.Inputs[0].Types[0].Type).ToBe("file:///work/packed.cwl#Stage[]")
+	Expect(t, echo.Requirements[0].Types[0].Name).ToBe("file:///work/packed.cwl#Stage")
+	Expect(t, echo.Outputs[0].ID).ToBe("file:///work/packed.cwl#echo/echo_out")
+	Expect(t, echo.Outputs[0].Types[0].Type).ToBe("stdout")
+
+	Expect(t, main.ID).ToBe("file:///work/packed.cwl#main")
+	Expect(t, main.Inputs[0].ID).ToBe("file:///work/packed.cwl#main/inp1")
+	Expect(t, main.Outputs[0].Source[0]).ToBe("file:///work/packed.cwl#main/step1/echo_out")
+	step1 := main.Steps[0]
+	Expect(t, step1.ID).ToBe("file:///work/packed.cwl#main/step1")
+	Expect(t, step1.Run.Value).ToBe("file:///work/packed.cwl#echo")
+	Expect(t, step1.Scatter[0]).ToBe("file:///work/packed.cwl#main/step1/text")
+	Expect(t, step1.In[0].ID).ToBe("file:///work/packed.cwl#main/step1/text")
+	Expect(t, step1.In[0].Source[0]).ToBe("file:///work/packed.cwl#main/inp1")
+	Expect(t, step1.Out[0].ID).ToBe("file:///work/packed.cwl#main/step1/echo_out")
+	step2 := main.Steps[1]
+	Expect(t, step2.Run.Value).ToBe("file:///work/tools/rev.cwl")
+	Expect(t, step2.In[0].Source).ToBe([]string{"file:///work/packed.cwl#main/step1/echo_out", "file:///work/packed.cwl#main/inp1"})
+
+	Expect(t, cwl.ShortName(step2.In[0].Source[0])).ToBe("echo_out")
+	Expect(t, cwl.ShortName(step2.Run.Value)).ToBe("rev.cwl")
+	Expect(t, cwl.ShortName("inp1")).ToBe("inp1")
+}
+
+func TestRoot_ResolveIdentifiers_without_id(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+class: CommandLineTool
+inputs:
+  - id: "#args.py"
+    type: File
+  - id: reference
+    type: File
+outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+	root.ResolveIdentifiers("file:///work/binding-test.cwl")
+	Expect(t, root.ID).ToBe("")
+	Expect(t, root.Inputs[0].ID).ToBe("file:///work/binding-test.cwl#args.py")
+	Expect(t, root.Inputs[1].ID).ToBe("file:///work/binding-test.cwl#reference")
+}
+
+func TestRoot_ResolveIdentifiers_stdin(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.1
+class: CommandLineTool
+baseCommand: cat
+inputs:
+  message: stdin
+outputs:
+  out: stdout
+`))
+	Expect(t, err).ToBe(nil)
+	root.ResolveIdentifiers("file:///work/cat.cwl")
+	Expect(t, root.Inputs[0].ID).ToBe("file:///work/cat.cwl#message")
+	Expect(t, root.Inputs[0].Types[0].Type).ToBe("stdin")
+	Expect(t, root.Outputs[0].Types[0].Type).ToBe("stdout")
+}
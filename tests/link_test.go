@@ -0,0 +1,125 @@
+package cwlgotest
+
+import (
+	"testing"
+
+	. "github.com/otiai10/mint"
+)
+
+func TestLoader_link(t *testing.T) {
+	loader, fetcher := newMemoryLoader(map[string]string{
+		"mem://host/wf/main.cwl": `
+cwlVersion: v1.0
+class: Workflow
+inputs:
+  input: File
+outputs:
+  output:
+    type: File
+    outputSource: sorted/output
+steps:
+  rev:
+    run: ../tools/revtool.cwl
+    in: {input: input}
+    out: [output]
+  rev_again:
+    run: ../tools/revtool.cwl
+    in: {input: rev/output}
+    out: [output]
+  sorted:
+    run:
+      class: Workflow
+      inputs: {input: File}
+      outputs: {output: {type: File, outputSource: sort/output}}
+      steps:
+        sort:
+          run: ../tools/packed.cwl#sorttool
+          in: {input: input}
+          out: [output]
+    in: {input: rev_again/output}
+    out: [output]
+`,
+		"mem://host/tools/revtool.cwl": `
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: rev
+inputs: {input: File}
+outputs: {output: stdout}
+`,
+		"mem://host/tools/packed.cwl": `
+cwlVersion: v1.0
+$graph:
+  - id: "#sorttool"
+    class: CommandLineTool
+    baseCommand: sort
+    inputs: {input: File}
+    outputs: {output: stdout}
+  - id: "#main"
+    class: Workflow
+    inputs: {input: File}
+    outputs: {output: {type: File, outputSource: step/output}}
+    steps:
+      step:
+        run: "#sorttool"
+        in: {input: input}
+        out: [output]
+`,
+	})
+	root, err := loader.Load("mem://host/wf/main.cwl")
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Path).ToBe("mem://host/wf/main.cwl")
+
+	rev := root.Steps[0].Run.Workflow
+	Expect(t, rev.Class).ToBe("CommandLineTool")
+	Expect(t, rev.BaseCommands[0]).ToBe("rev")
+	Expect(t, rev.Path).ToBe("mem://host/tools/revtool.cwl")
+	// Shared tools are loaded only once.
+	Expect(t, root.Steps[1].Run.Workflow == rev).ToBe(true)
+	Expect(t, fetcher.fetched["mem://host/tools/revtool.cwl"]).ToBe(1)
+
+	sorted := root.Steps[2].Run.Workflow
+	Expect(t, sorted.Class).ToBe("Workflow")
+	sorttool := sorted.Steps[0].Run.Workflow
+	Expect(t, sorttool.ID).ToBe("#sorttool")
+	Expect(t, sorttool.BaseCommands[0]).ToBe("sort")
+	Expect(t, sorttool.Path).ToBe("mem://host/tools/packed.cwl")
+
+	// Sibling "$graph" entries are linked by fragment.
+	packed, err := loader.Load("mem://host/tools/packed.cwl#main")
+	Expect(t, err).ToBe(nil)
+	Expect(t, packed.ID).ToBe("#main")
+	Expect(t, packed.Steps[0].Run.Workflow == sorttool).ToBe(true)
+}
+
+func TestLoader_link_cycle(t *testing.T) {
+	loader, _ := newMemoryLoader(map[string]string{
+		"mem://host/a.cwl": `
+class: Workflow
+steps:
+  b:
+    run: b.cwl
+`,
+		"mem://host/b.cwl": `
+class: Workflow
+steps:
+  a:
+    run: a.cwl
+`,
+	})
+	_, err := loader.Load("mem://host/a.cwl")
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, err.Error()).ToBe("Run cycle: mem://host/a.cwl -> mem://host/b.cwl -> mem://host/a.cwl")
+}
+
+func TestLoader_link_not_found(t *testing.T) {
+	loader, _ := newMemoryLoader(map[string]string{
+		"mem://host/a.cwl": `
+class: Workflow
+steps:
+  b:
+    run: "#missing"
+`,
+	})
+	_, err := loader.Load("mem://host/a.cwl")
+	Expect(t, err).Not().ToBe(nil)
+}
@@ -0,0 +1,168 @@
+package cwlgotest
+
+import (
+	"fmt"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+// memoryFetcher serves documents from memory, keyed by URI.
+type memoryFetcher struct {
+	docs    map[string]string
+	fetched map[string]int
+}
+
+func (f *memoryFetcher) Fetch(uri string) ([]byte, error) {
+	f.fetched[uri]++
+	if doc, ok := f.docs[uri]; ok {
+		return []byte(doc), nil
+	}
+	return nil, fmt.Errorf("not found: %s", uri)
+}
+
+func newMemoryLoader(docs map[string]string) (*cwl.Loader, *memoryFetcher) {
+	fetcher := &memoryFetcher{docs: docs, fetched: map[string]int{}}
+	loader := cwl.NewLoader()
+	loader.Fetchers["mem"] = fetcher
+	return loader, fetcher
+}
+
+func TestLoader_import(t *testing.T) {
+	loader, fetcher := newMemoryLoader(map[string]string{
+		"mem://host/tools/main.cwl": `
+cwlVersion: v1.0
+class: CommandLineTool
+requirements:
+  - class: InlineJavascriptRequirement
+    expressionLib:
+      - $include: lib/util.js
+  - class: SchemaDefRequirement
+    types:
+      - $import: types.yml
+      - $import: more.yml#Color
+hints:
+  - $import: docker.yml
+inputs:
+  item:
+    type: types.yml#Item
+outputs: []
+`,
+		"mem://host/tools/lib/util.js": `function twice(x) { return x * 2; }`,
+		"mem://host/tools/types.yml": `
+- name: Item
+  type: record
+  fields:
+    - name: label
+      type: string
+- name: Pair
+  type: record
+  fields: []
+`,
+		"mem://host/tools/more.yml": `
+- name: Size
+  type: enum
+  symbols: [S, M, L]
+- name: Color
+  type: enum
+  symbols: [red, green]
+`,
+		"mem://host/tools/docker.yml": `
+class: DockerRequirement
+dockerPull: debian:8
+`,
+	})
+	root, err := loader.Load("mem://host/tools/main.cwl")
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Path).ToBe("mem://host/tools/main.cwl")
+	Expect(t, root.Requirements[0].ExpressionLib[0].Value).ToBe("function twice(x) { return x * 2; }")
+	Expect(t, len(root.Requirements[1].Types)).ToBe(3)
+	Expect(t, root.Requirements[1].Types[0].Name).ToBe("Item")
+	Expect(t, root.Requirements[1].Types[1].Name).ToBe("Pair")
+	Expect(t, root.Requirements[1].Types[2].Name).ToBe("Color")
+	Expect(t, root.Requirements[1].Types[2].Symbols).ToBe([]string{"red", "green"})
+	Expect(t, root.Hints[0].Class).ToBe("DockerRequirement")
+	Expect(t, root.Hints[0].DockerPull).ToBe("debian:8")
+
+	// Cached documents are not fetched again.
+	_, err = loader.Load("mem://host/tools/main.cwl")
+	Expect(t, err).ToBe(nil)
+	Expect(t, fetcher.fetched["mem://host/tools/types.yml"]).ToBe(1)
+}
+
+func TestLoader_import_cycle(t *testing.T) {
+	loader, _ := newMemoryLoader(map[string]string{
+		"mem://host/a.cwl": `
+class: CommandLineTool
+hints:
+  - $import: b.yml
+`,
+		"mem://host/b.yml": `
+class: EnvVarRequirement
+envDef:
+  $import: a.cwl
+`,
+	})
+	_, err := loader.Load("mem://host/a.cwl")
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, err.Error()).ToBe("Import cycle: mem://host/a.cwl -> mem://host/b.yml -> mem://host/a.cwl")
+}
+
+func TestLoader_import_same_document(t *testing.T) {
+	loader, _ := newMemoryLoader(map[string]string{
+		"mem://host/a.cwl": `
+class: CommandLineTool
+inputs:
+  - id: first
+    type: string
+    inputBinding:
+      position: 1
+  - $mixin: "#first"
+    id: second
+outputs: []
+`,
+		"mem://host/b.cwl": `
+class: CommandLineTool
+inputs:
+  - id: loop
+    $import: "#loop"
+outputs: []
+`,
+	})
+	root, err := loader.Load("mem://host/a.cwl")
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(root.Inputs)).ToBe(2)
+	Expect(t, root.Inputs[1].ID).ToBe("second")
+	Expect(t, root.Inputs[1].Types[0].Type).ToBe("string")
+	Expect(t, root.Inputs[1].Binding.Position).ToBe(1)
+
+	_, err = loader.Load("mem://host/b.cwl")
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, err.Error()).ToBe("Import cycle: mem://host/b.cwl -> mem://host/b.cwl#loop -> mem://host/b.cwl#loop")
+}
+
+func TestLoader_unknown_scheme(t *testing.T) {
+	loader := cwl.NewLoader()
+	_, err := loader.Load("ftp://host/a.cwl")
+	Expect(t, err).Not().ToBe(nil)
+}
+
+func TestLoader_zero_value(t *testing.T) {
+	fetcher := &memoryFetcher{docs: map[string]string{
+		"mem://host/a.cwl": `
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: echo
+inputs: []
+outputs: []
+`,
+	}, fetched: map[string]int{}}
+	loader := &cwl.Loader{Fetchers: map[string]cwl.Fetcher{"mem": fetcher}}
+	root, err := loader.Load("mem://host/a.cwl")
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Class).ToBe("CommandLineTool")
+
+	_, err = (&cwl.Loader{}).Load("ftp://host/a.cwl")
+	Expect(t, err).Not().ToBe(nil)
+}
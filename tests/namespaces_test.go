@@ -0,0 +1,75 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const namespacedDocument = `
+cwlVersion: v1.0
+class: CommandLineTool
+$namespaces:
+  edam: http://edamontology.org/
+  ex: http://example.com/
+hints:
+  ex:BlibberBlubberFakeRequirement:
+    fakeField: fraggleFroogle
+inputs:
+  input:
+    type: File
+    format: edam:format_2330
+    default:
+      class: File
+      location: whale.txt
+      format: edam:format_1929
+outputs:
+  output:
+    type: File
+    format: $(inputs.input.format)
+`
+
+func TestRoot_ExpandNamespaces(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(namespacedDocument))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Inputs[0].Format).ToBe("edam:format_2330")
+
+	root.ExpandNamespaces()
+	Expect(t, root.Hints[0].Class).ToBe("http://example.com/BlibberBlubberFakeRequirement")
+	Expect(t, root.Inputs[0].Format).ToBe("http://edamontology.org/format_2330")
+	Expect(t, root.Inputs[0].Default.Self.(map[string]interface{})["format"]).ToBe("http://edamontology.org/format_1929")
+	Expect(t, root.Outputs[0].Format).ToBe("$(inputs.input.format)")
+
+	Expect(t, root.Namespaces.Compact("http://edamontology.org/format_2330")).ToBe("edam:format_2330")
+	Expect(t, root.Namespaces.Compact("http://unknown.org/format")).ToBe("http://unknown.org/format")
+	Expect(t, root.Namespaces.Equal("edam:format_2330", "http://edamontology.org/format_2330")).ToBe(true)
+	Expect(t, root.Namespaces.Equal("edam:format_2330", "edam:format_1929")).ToBe(false)
+	Expect(t, root.Namespaces.Expand("undeclared:foo")).ToBe("undeclared:foo")
+}
+
+func TestNamespaces_Compact_tie(t *testing.T) {
+	ns := cwl.Namespaces{{"edam": "http://edamontology.org/", "EDAM": "http://edamontology.org/", "ed": "http://edamontology.org/format_"}}
+	for i := 0; i < 20; i++ {
+		Expect(t, ns.Compact("http://edamontology.org/data_1234")).ToBe("EDAM:data_1234")
+	}
+	Expect(t, ns.Compact("http://edamontology.org/format_2330")).ToBe("ed:2330")
+}
+
+func TestNamespaces_Compact_decoded(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: CommandLineTool
+$namespaces:
+  zed: http://edamontology.org/
+  edam: http://edamontology.org/
+inputs: []
+outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(root.Namespaces)).ToBe(2)
+	Expect(t, root.Namespaces.Compact("http://edamontology.org/format_2330")).ToBe("edam:format_2330")
+}
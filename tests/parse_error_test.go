@@ -0,0 +1,69 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const invalidDocument = `cwlVersion: v1.0
+class: Workflow
+inputs:
+  inp1:
+    type: string
+    inputBinding:
+      position: first
+outputs: []
+steps:
+  step1:
+    run: echo.cwl
+    in:
+      echo_in1:
+        source: inp1
+        valueFrom:
+          foo: bar
+    out: [echo_out]
+`
+
+func TestDecode_parse_error(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(invalidDocument))
+	Expect(t, err).TypeOf("cwl.ParseErrors")
+	errs := err.(cwl.ParseErrors)
+	Expect(t, len(errs)).ToBe(2)
+
+	Expect(t, errs[0].Path).ToBe("inputs.inp1.inputBinding.position")
+	Expect(t, errs[0].Expected).ToBe("int or expression")
+	Expect(t, errs[0].Actual).ToBe("string")
+	Expect(t, errs[0].Line).ToBe(7)
+	Expect(t, errs[0].Column).ToBe(17)
+
+	Expect(t, errs[1].Path).ToBe("steps.step1.in.echo_in1.valueFrom")
+	Expect(t, errs[1].Expected).ToBe("string")
+	Expect(t, errs[1].Actual).ToBe("mapping")
+	Expect(t, errs[1].Line).ToBe(16)
+	Expect(t, errs[1].Column).ToBe(11)
+	Expect(t, errs[1].Error()).ToBe("Parse error at line 16, column 11: steps.step1.in.echo_in1.valueFrom: expected string but got mapping")
+
+	// Valid values are still decoded
+	Expect(t, root.Inputs[0].ID).ToBe("inp1")
+	Expect(t, root.Steps[0].In[0].Source[0]).ToBe("inp1")
+}
+
+func TestUnmarshalMap_parse_error(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.UnmarshalMap(map[string]interface{}{
+		"class":       "CommandLineTool",
+		"cwlVersion":  1.0,
+		"baseCommand": []interface{}{"echo", true},
+	})
+	errs := err.(cwl.ParseErrors)
+	Expect(t, len(errs)).ToBe(2)
+	Expect(t, errs[0].Path).ToBe("baseCommand[1]")
+	Expect(t, errs[0].Actual).ToBe("boolean")
+	Expect(t, errs[1].Path).ToBe("cwlVersion")
+	Expect(t, errs[1].Line).ToBe(0)
+	Expect(t, root.BaseCommands[0]).ToBe("echo")
+}
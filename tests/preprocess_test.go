@@ -0,0 +1,73 @@
+package cwlgotest
+
+import (
+	"testing"
+
+	. "github.com/otiai10/mint"
+)
+
+func TestLoader_mixin(t *testing.T) {
+	loader, _ := newMemoryLoader(map[string]string{
+		"mem://host/main.cwl": `
+cwlVersion: v1.0
+$base: mem://host/tools/
+$namespaces:
+  edam: http://edamontology.org/
+$schemas:
+  - EDAM.owl
+$mixin: common.yml
+class: CommandLineTool
+baseCommand: [sort, -r]
+inputs:
+  - $mixin: input.yml
+    id: file
+`,
+		"mem://host/tools/common.yml": `
+$namespaces:
+  edam: http://example.com/overridden/
+  gx: http://galaxyproject.org/formats/
+$schemas:
+  - gx_edam.ttl
+class: ExpressionTool
+baseCommand: sort
+stdout: out.txt
+outputs:
+  - id: out
+    type: File
+    format: edam:format_1929
+`,
+		"mem://host/tools/input.yml": `
+id: input
+type: File
+format: edam:format_2330
+`,
+	})
+	root, err := loader.Load("mem://host/main.cwl")
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Class).ToBe("CommandLineTool")
+	Expect(t, []string(root.BaseCommands)).ToBe([]string{"sort", "-r"})
+	Expect(t, root.Stdout).ToBe("out.txt")
+	Expect(t, root.Inputs[0].ID).ToBe("file")
+	Expect(t, root.Inputs[0].Types[0].Type).ToBe("File")
+	Expect(t, root.Inputs[0].Format).ToBe("edam:format_2330")
+	Expect(t, len(root.Namespaces)).ToBe(2)
+	Expect(t, root.Namespaces[0]["edam"]).ToBe("http://edamontology.org/")
+	// CURIEs of the imported document are expanded by its own namespaces.
+	Expect(t, root.Outputs[0].Format).ToBe("http://example.com/overridden/format_1929")
+	Expect(t, root.Namespaces[1]["gx"]).ToBe("http://galaxyproject.org/formats/")
+	Expect(t, []string(root.Schemas)).ToBe([]string{"mem://host/tools/EDAM.owl", "mem://host/tools/gx_edam.ttl"})
+}
+
+func TestLoader_mixin_cycle(t *testing.T) {
+	loader, _ := newMemoryLoader(map[string]string{
+		"mem://host/a.yml": `
+$mixin: b.yml
+class: CommandLineTool
+`,
+		"mem://host/b.yml": `
+$mixin: a.yml
+`,
+	})
+	_, err := loader.Load("mem://host/a.yml")
+	Expect(t, err).Not().ToBe(nil)
+}
@@ -28,10 +28,10 @@ func TestDecode_record_output(t *testing.T) {
 	Expect(t, root.Outputs[0].Types[0].Type).ToBe("record")
 	Expect(t, root.Outputs[0].Types[0].Fields[0].Name).ToBe("ofoo")
 	Expect(t, root.Outputs[0].Types[0].Fields[0].Types[0].Type).ToBe("File")
-	Expect(t, root.Outputs[0].Types[0].Fields[0].Binding.Glob[0]).ToBe("foo")
+	Expect(t, root.Outputs[0].Types[0].Fields[0].OutputBinding.Glob[0]).ToBe("foo")
 	Expect(t, root.Outputs[0].Types[0].Fields[1].Name).ToBe("obar")
 	Expect(t, root.Outputs[0].Types[0].Fields[1].Types[0].Type).ToBe("File")
-	Expect(t, root.Outputs[0].Types[0].Fields[1].Binding.Glob[0]).ToBe("bar")
+	Expect(t, root.Outputs[0].Types[0].Fields[1].OutputBinding.Glob[0]).ToBe("bar")
 	Expect(t, root.Arguments[0].Binding.ValueFrom.Key()).ToBe("cat")
 	Expect(t, root.Arguments[0].Binding.Position).ToBe(1)
 	Expect(t, root.Arguments[1].Binding.ValueFrom.Key()).ToBe("> foo")
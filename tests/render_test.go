@@ -0,0 +1,130 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const rendered = `
+cwlVersion: v1.0
+$graph:
+  - id: wc
+    class: CommandLineTool
+    baseCommand: wc
+    inputs:
+      file: File
+    outputs:
+      lines: int
+  - id: main
+    class: Workflow
+    requirements:
+      ScatterFeatureRequirement: {}
+      SubworkflowFeatureRequirement: {}
+    inputs:
+      files:
+        type: File[]
+        label: Input "files"
+    outputs:
+      lines:
+        type: int[]
+        outputSource: count/lines
+      words:
+        type: int
+        outputSource: sub/words
+    steps:
+      count:
+        scatter: file
+        in:
+          file: files
+        out: [lines]
+        run: "#wc"
+      sub:
+        label: Split into words
+        in:
+          file:
+            source: files
+            valueFrom: $(self[0])
+        out: [words]
+        run:
+          class: Workflow
+          inputs:
+            file: File
+          outputs:
+            words:
+              type: int
+              outputSource: split/words
+          steps:
+            split:
+              label: split.sh
+              in:
+                file: file
+              out: [words]
+              run: split.cwl
+`
+
+func TestDAG_DOT(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(rendered))
+	Expect(t, err).ToBe(nil)
+	g, err := cwl.NewDAG(root)
+	Expect(t, err).ToBe(nil)
+	Expect(t, g.DOT()).ToBe(`digraph workflow {
+  rankdir=LR;
+  "files" [label="files\nInput \"files\"", shape=ellipse, style=filled, fillcolor="#94DDF4"];
+  "count" [label="count", shape=box3d];
+  "lines" [label="lines", shape=ellipse, style=filled, fillcolor="#94DDB4"];
+  "words" [label="words", shape=ellipse, style=filled, fillcolor="#94DDB4"];
+  subgraph "cluster_sub" {
+    label="sub\nSplit into words";
+    "sub/file" [label="file", shape=ellipse, style=filled, fillcolor="#94DDF4"];
+    "sub/split" [label="split\nsplit.sh", shape=box];
+    "sub/words" [label="words", shape=ellipse, style=filled, fillcolor="#94DDB4"];
+  }
+  "sub/file" -> "sub/split" [label="file"];
+  "sub/split" -> "sub/words" [label="words"];
+  "files" -> "count" [label="file"];
+  "files" -> "sub/file";
+  "count" -> "lines" [label="lines"];
+  "sub/words" -> "words";
+}
+`)
+
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader(dag))
+	Expect(t, err).ToBe(nil)
+	g, err = cwl.NewDAG(root)
+	Expect(t, err).ToBe(nil)
+	Expect(t, strings.Contains(g.DOT(), `  "count" -> "report" [label="lines → lines"];`)).ToBe(true)
+}
+
+func TestDAG_Mermaid(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(rendered))
+	Expect(t, err).ToBe(nil)
+	g, err := cwl.NewDAG(root)
+	Expect(t, err).ToBe(nil)
+	Expect(t, g.Mermaid()).ToBe(`flowchart LR
+  n0(["files<br/>Input #quot;files#quot;"])
+  n1[["count"]]
+  n5(["lines"])
+  n6(["words"])
+  subgraph w0 ["sub<br/>Split into words"]
+    n2(["file"])
+    n3["split<br/>split.sh"]
+    n4(["words"])
+  end
+  n2 -->|"file"| n3
+  n3 -->|"words"| n4
+  n0 -->|"file"| n1
+  n0 --> n2
+  n1 -->|"lines"| n5
+  n4 --> n6
+  classDef input fill:#94DDF4
+  classDef output fill:#94DDB4
+  class n0,n2 input
+  class n4,n5,n6 output
+`)
+}
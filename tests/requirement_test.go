@@ -0,0 +1,157 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const requirementsTool = `
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: bwa
+requirements:
+  - class: DockerRequirement
+    dockerLoad: https://example.com/bwa.tar
+    dockerImport: https://example.com/bwa.tar.gz
+    dockerImageId: bwa:0.7
+    dockerFile: "FROM debian:8"
+  - class: SoftwareRequirement
+    packages:
+      - package: bwa
+        version: ["0.7.17"]
+        specs: [https://identifiers.org/rrid/RRID:SCR_010910]
+  - class: ResourceRequirement
+    coresMin: 2
+    coresMax: $(inputs.threads)
+    ramMin: 1024
+    ramMax: $(inputs.ram * 2)
+    tmpdirMin: 512
+    tmpdirMax: 1024
+    outdirMin: 2048
+    outdirMax: $(inputs.outdir)
+  - class: EnvVarRequirement
+    envDef:
+      - envName: HOME
+        envValue: $(runtime.outdir)
+  - class: InitialWorkDirRequirement
+    listing:
+      - class: File
+        location: ref.fa
+      - entryname: config.txt
+        entry: $(inputs.config)
+        writable: true
+      - $(inputs.reads)
+      - class: Directory
+        location: index
+        listing: [{class: File, basename: ref.fa.bwt, contents: bwt}]
+  - class: ShellCommandRequirement
+inputs: []
+outputs: []
+`
+
+func TestDecode_requirements(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.DecodeWithOptions(strings.NewReader(requirementsTool), cwl.DecodeOptions{Strict: true})
+	Expect(t, err).ToBe(nil)
+
+	docker := root.Requirements.Find("DockerRequirement")
+	Expect(t, docker.DockerLoad).ToBe("https://example.com/bwa.tar")
+	Expect(t, docker.DockerImport).ToBe("https://example.com/bwa.tar.gz")
+	Expect(t, docker.DockerImageID).ToBe("bwa:0.7")
+	Expect(t, docker.DockerFile).ToBe("FROM debian:8")
+
+	software := root.Requirements.Find("SoftwareRequirement")
+	Expect(t, software.Packages).ToBe([]cwl.SoftwarePackage{
+		{Package: "bwa", Versions: []string{"0.7.17"}, Specs: []string{"https://identifiers.org/rrid/RRID:SCR_010910"}},
+	})
+
+	resource := root.Requirements.Find("ResourceRequirement").ResourceRequirement
+	Expect(t, resource).ToBe(cwl.ResourceRequirement{
+		CoresMin: 2, CoresMaxExpression: "$(inputs.threads)",
+		RAMMin: 1024, RAMMaxExpression: "$(inputs.ram * 2)",
+		TmpdirMin: 512, TmpdirMax: 1024,
+		OutdirMin: 2048, OutdirMaxExpression: "$(inputs.outdir)",
+	})
+
+	env := root.Requirements.Find("EnvVarRequirement")
+	Expect(t, env.EnvDef).ToBe([]cwl.EnvDef{{Name: "HOME", Value: "$(runtime.outdir)"}})
+
+	listing := root.Requirements.Find("InitialWorkDirRequirement").Listing
+	Expect(t, len(listing)).ToBe(4)
+	Expect(t, listing[0].Class).ToBe("File")
+	Expect(t, listing[0].Location).ToBe("ref.fa")
+	Expect(t, listing[1].EntryName).ToBe("config.txt")
+	Expect(t, listing[1].Dirent.Entry).ToBe("$(inputs.config)")
+	Expect(t, listing[1].Writable).ToBe(true)
+	Expect(t, listing[2].Expression).ToBe("$(inputs.reads)")
+	Expect(t, listing[3].Listing[0].Contents).ToBe("bwt")
+
+	Expect(t, root.Requirements.Find("ShellCommandRequirement") != nil).ToBe(true)
+	Expect(t, root.Requirements.Find("ScatterFeatureRequirement") == nil).ToBe(true)
+}
+
+func TestDecode_requirements_mapForm(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: CommandLineTool
+requirements:
+  SoftwareRequirement:
+    packages:
+      samtools: [https://identifiers.org/rrid/RRID:SCR_002105]
+      bwa:
+        version: ["0.7.17"]
+  EnvVarRequirement:
+    envDef:
+      LANG: C
+inputs: []
+outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Requirements[0].Packages).ToBe([]cwl.SoftwarePackage{
+		{Package: "samtools", Specs: []string{"https://identifiers.org/rrid/RRID:SCR_002105"}},
+		{Package: "bwa", Versions: []string{"0.7.17"}},
+	})
+	Expect(t, root.Requirements[1].EnvDef).ToBe([]cwl.EnvDef{{Name: "LANG", Value: "C"}})
+}
+
+func TestDecode_requirements_fractional(t *testing.T) {
+	const tool = `
+cwlVersion: v1.2
+class: CommandLineTool
+requirements:
+  ResourceRequirement:
+    coresMin: 0.5
+    coresMax: 2
+    ramMin: 1536.5
+    tmpdirMin: 0.25
+    outdirMax: $(inputs.size * 1.5)
+inputs: []
+outputs: []
+`
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(tool))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Requirements[0].ResourceRequirement).ToBe(cwl.ResourceRequirement{
+		CoresMin: 0.5, CoresMax: 2,
+		RAMMin: 1537, TmpdirMin: 1, OutdirMaxExpression: "$(inputs.size * 1.5)",
+	})
+
+	// Fractional values are not allowed before v1.2.
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader(strings.Replace(tool, "v1.2", "v1.1", 1)))
+	errs, ok := err.(cwl.ParseErrors)
+	Expect(t, ok).ToBe(true)
+	messages := []string{}
+	for _, e := range errs {
+		messages = append(messages, e.Path+": "+e.Message)
+	}
+	Expect(t, messages).ToBe([]string{
+		"requirements.ResourceRequirement.coresMin: fractional value in \"coresMin\" requires cwlVersion v1.2 or later",
+		"requirements.ResourceRequirement.ramMin: fractional value in \"ramMin\" requires cwlVersion v1.2 or later",
+		"requirements.ResourceRequirement.tmpdirMin: fractional value in \"tmpdirMin\" requires cwlVersion v1.2 or later",
+	})
+}
@@ -0,0 +1,129 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+func TestDecode_step_fields(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.1
+class: Workflow
+inputs:
+  file1: File
+outputs: []
+steps:
+  count:
+    label: Count lines
+    doc: Counts lines of the file
+    hints:
+      ResourceRequirement:
+        coresMin: 2
+    in:
+      file1: file1
+      files: [file1, file1]
+      content:
+        source: file1
+        default: hello
+        loadContents: true
+      extra:
+        id: other
+        valueFrom: $(1)
+      empty: {}
+    out: [lines, {id: words}]
+    run: wc.cwl
+`))
+	Expect(t, err).ToBe(nil)
+	step := root.Steps[0]
+	Expect(t, step.ID).ToBe("count")
+	Expect(t, step.Label).ToBe("Count lines")
+	Expect(t, step.Doc).ToBe("Counts lines of the file")
+	Expect(t, step.Hints[0].Class).ToBe("ResourceRequirement")
+	Expect(t, step.Hints[0].CoresMin).ToBe(2.0)
+	Expect(t, len(step.In)).ToBe(5)
+	Expect(t, step.In[0].ID).ToBe("file1")
+	Expect(t, step.In[0].Source).ToBe([]string{"file1"})
+	Expect(t, step.In[1].ID).ToBe("files")
+	Expect(t, step.In[1].Source).ToBe([]string{"file1", "file1"})
+	Expect(t, step.In[2].ID).ToBe("content")
+	Expect(t, step.In[2].Source).ToBe([]string{"file1"})
+	Expect(t, step.In[2].Default.Self).ToBe("hello")
+	Expect(t, step.In[2].LoadContents).ToBe(true)
+	Expect(t, step.In[3].ID).ToBe("other")
+	Expect(t, step.In[3].Source).ToBe([]string(nil))
+	Expect(t, step.In[3].ValueFrom).ToBe("$(1)")
+	Expect(t, step.In[4].ID).ToBe("empty")
+	Expect(t, step.Out[0].ID).ToBe("lines")
+	Expect(t, step.Out[1].ID).ToBe("words")
+}
+
+func TestDecode_step_inputs_list(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs: []
+outputs: []
+steps:
+  - id: echo
+    in:
+      - source: [a, b]
+        id: message
+        linkMerge: merge_flattened
+        valueFrom: $(self.join(" "))
+    out:
+      - id: out
+    run: echo.cwl
+`))
+	Expect(t, err).ToBe(nil)
+	in := root.Steps[0].In[0]
+	Expect(t, in.ID).ToBe("message")
+	Expect(t, in.Source).ToBe([]string{"a", "b"})
+	Expect(t, in.LinkMerge).ToBe("merge_flattened")
+	Expect(t, in.ValueFrom).ToBe(`$(self.join(" "))`)
+	Expect(t, root.Steps[0].Out[0].ID).ToBe("out")
+}
+
+func TestDecode_step_inputs_invalid(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.DecodeWithOptions(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs: []
+outputs: []
+steps:
+  echo:
+    in:
+      message:
+        source: a
+        linkMerge: merge_all
+    out: [{id: out, doc: output}]
+    run: echo.cwl
+`), cwl.DecodeOptions{Strict: true})
+	Expect(t, err).Not().ToBe(nil)
+	errs := err.(cwl.ParseErrors)
+	Expect(t, len(errs)).ToBe(1)
+	Expect(t, errs[0].Path).ToBe("steps.echo.in.message.linkMerge")
+
+	err = root.DecodeWithOptions(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs: []
+outputs: []
+steps:
+  echo:
+    in: {message: a}
+    out: [{id: out, doc: output}]
+    run: echo.cwl
+`), cwl.DecodeOptions{Strict: true})
+	Expect(t, err).Not().ToBe(nil)
+	unknowns := err.(cwl.UnknownFields)
+	Expect(t, len(unknowns)).ToBe(1)
+	Expect(t, unknowns[0].Path).ToBe("steps.echo.out[0]")
+	Expect(t, unknowns[0].Record).ToBe("WorkflowStepOutput")
+	Expect(t, unknowns[0].Key).ToBe("doc")
+}
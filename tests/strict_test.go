@@ -0,0 +1,56 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const misspelledDocument = `cwlVersion: v1.0
+class: CommandLineTool
+$namespaces:
+  ex: http://example.com/
+baseCommand: echo
+hints:
+  DockerRequirement:
+    dockerPul: debian:8
+  ex:SomeExtension:
+    whatever: true
+inputs:
+  message:
+    type: string
+    inputBindng:
+      position: 1
+    ex:note: extension field
+outputs: []
+`
+
+func TestDecodeWithOptions_strict(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.DecodeWithOptions(strings.NewReader(misspelledDocument), cwl.DecodeOptions{Strict: true})
+	Expect(t, err).TypeOf("cwl.UnknownFields")
+	unknowns := err.(cwl.UnknownFields)
+	Expect(t, len(unknowns)).ToBe(2)
+
+	Expect(t, unknowns[0].Path).ToBe("hints.DockerRequirement")
+	Expect(t, unknowns[0].Record).ToBe("DockerRequirement")
+	Expect(t, unknowns[0].Key).ToBe("dockerPul")
+	Expect(t, unknowns[0].Suggestions[0]).ToBe("dockerPull")
+	Expect(t, unknowns[0].Line).ToBe(8)
+
+	Expect(t, unknowns[1].Path).ToBe("inputs.message")
+	Expect(t, unknowns[1].Key).ToBe("inputBindng")
+	Expect(t, unknowns[1].Suggestions).ToBe([]string{"inputBinding"})
+	Expect(t, unknowns[1].Error()).ToBe(`Strict error at line 15, column 7: inputs.message: unknown field "inputBindng" for InputParameter, did you mean "inputBinding"?`)
+
+	Expect(t, unknowns.ByPath()["inputs.message"]).ToBe([]string{"inputBindng"})
+}
+
+func TestDecode_not_strict(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(misspelledDocument))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Inputs[0].Binding).ToBe((*cwl.CommandLineBinding)(nil))
+}
@@ -0,0 +1,122 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+func TestParseTypes(t *testing.T) {
+	node := cwl.ParseTypes([]cwl.Type{{Type: "File[]?"}})
+	Expect(t, node.Kind).ToBe(cwl.KindUnion)
+	Expect(t, node.Types[0].Kind).ToBe(cwl.KindNull)
+	Expect(t, node.Types[1].Kind).ToBe(cwl.KindArray)
+	Expect(t, node.Types[1].Items.Kind).ToBe(cwl.KindFile)
+	Expect(t, node.String()).ToBe("File[]?")
+	Expect(t, node.Nullable()).ToBe(true)
+
+	node = cwl.ParseTypes([]cwl.Type{{Type: "null"}, {Type: "array", Items: []cwl.Type{{Type: "string"}}}})
+	Expect(t, node.Equal(cwl.ParseTypes([]cwl.Type{{Type: "string[]?"}}))).ToBe(true)
+	Expect(t, node.Equal(cwl.ParseTypes([]cwl.Type{{Type: "string[]"}}))).ToBe(false)
+
+	node = cwl.ParseTypes([]cwl.Type{{Type: "#Stage"}})
+	Expect(t, node.Kind).ToBe(cwl.KindNamed)
+	Expect(t, node.Name).ToBe("#Stage")
+	Expect(t, node.Equal(cwl.ParseTypes([]cwl.Type{{Type: "sd.yml#Stage"}}))).ToBe(true)
+
+	node = cwl.ParseTypes([]cwl.Type{{Type: "stdout"}})
+	Expect(t, node.Kind).ToBe(cwl.KindFile)
+
+	// No type is not a union nor Any
+	Expect(t, cwl.ParseTypes(nil) == nil).ToBe(true)
+	node = cwl.ParseTypes([]cwl.Type{{Type: "array"}})
+	Expect(t, node.String()).ToBe("undeclared[]")
+}
+
+func TestAssignable(t *testing.T) {
+	parse := func(s string) *cwl.TypeNode {
+		return cwl.ParseTypes([]cwl.Type{{Type: s}})
+	}
+	Expect(t, cwl.Assignable(parse("int"), parse("long"))).ToBe(true)
+	Expect(t, cwl.Assignable(parse("long"), parse("int"))).ToBe(false)
+	Expect(t, cwl.Assignable(parse("int"), parse("double?"))).ToBe(true)
+	Expect(t, cwl.Assignable(parse("int?"), parse("int"))).ToBe(false)
+	Expect(t, cwl.Assignable(parse("File[]"), parse("File[]?"))).ToBe(true)
+	Expect(t, cwl.Assignable(parse("File"), parse("Any"))).ToBe(true)
+	Expect(t, cwl.Assignable(parse("null"), parse("Any"))).ToBe(false)
+	Expect(t, cwl.Assignable(parse("Any"), parse("string"))).ToBe(true)
+	Expect(t, cwl.Assignable(parse("string"), parse("File"))).ToBe(false)
+	Expect(t, cwl.Assignable(nil, parse("Any"))).ToBe(false)
+	Expect(t, cwl.Assignable(parse("Any"), nil)).ToBe(false)
+	Expect(t, cwl.Assignable(cwl.ParseTypes([]cwl.Type{{Type: "array"}}), parse("string[]"))).ToBe(false)
+
+	small := &cwl.TypeNode{Kind: cwl.KindEnum, Symbols: []string{"#a"}}
+	large := &cwl.TypeNode{Kind: cwl.KindEnum, Symbols: []string{"a", "b"}}
+	Expect(t, cwl.Assignable(small, large)).ToBe(true)
+	Expect(t, cwl.Assignable(large, small)).ToBe(false)
+}
+
+func TestRoot_ResolveType(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: echo
+requirements:
+  - class: SchemaDefRequirement
+    types:
+      - name: Mode
+        type: enum
+        symbols: [fast, slow]
+      - name: Stage
+        type: record
+        fields:
+          - name: mode
+            type: Mode
+          - name: inner
+            type:
+              type: record
+              name: Inner
+              fields:
+                count: int
+          - name: label
+            type: string?
+inputs:
+  stages: "#Stage[]"
+  inner: Inner
+  unknown: Unknown
+outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(root.TypeDefs())).ToBe(3)
+
+	node, err := root.ResolveType(root.Inputs[0].Types)
+	Expect(t, err).ToBe(nil)
+	Expect(t, node.String()).ToBe("Stage[]")
+	stage := node.Items
+	Expect(t, stage.Kind).ToBe(cwl.KindRecord)
+	Expect(t, stage.Fields[0].Name).ToBe("mode")
+	Expect(t, stage.Fields[0].Type.Kind).ToBe(cwl.KindEnum)
+	Expect(t, stage.Fields[0].Type.Symbols).ToBe([]string{"fast", "slow"})
+	Expect(t, stage.Fields[1].Type.Fields[0].Type.Kind).ToBe(cwl.KindInt)
+
+	inner, err := root.ResolveType(root.Inputs[1].Types)
+	Expect(t, err).ToBe(nil)
+	Expect(t, inner.Equal(stage.Fields[1].Type)).ToBe(true)
+
+	// A record without the optional field is assignable
+	partial := &cwl.TypeNode{Kind: cwl.KindRecord, Fields: []cwl.TypeField{
+		{Name: "mode", Type: &cwl.TypeNode{Kind: cwl.KindEnum, Symbols: []string{"fast"}}},
+		{Name: "inner", Type: inner},
+	}}
+	Expect(t, cwl.Assignable(partial, stage)).ToBe(true)
+	Expect(t, cwl.Assignable(stage, partial)).ToBe(false)
+
+	_, err = root.ResolveType(root.Inputs[2].Types)
+	Expect(t, err.Error()).ToBe("type Unknown is not defined")
+
+	_, err = root.ResolveType(nil)
+	Expect(t, err.Error()).ToBe("type is not declared")
+}
@@ -0,0 +1,221 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+func TestUpgradeYAML_draft3(t *testing.T) {
+	src := `
+cwlVersion: draft-3
+class: Workflow
+description: Compile a java file
+requirements:
+  - class: ExpressionEngineRequirement
+    id: "#js"
+    engineCommand: cwlNodeEngine.js
+inputs:
+  - id: "#inp"
+    type: File
+  - id: "#args.py"
+    type: File
+outputs:
+  - id: "#classfile"
+    type: File
+    source: "#compile.classfile"
+steps:
+  - id: "#compile"
+    run:
+      class: CommandLineTool
+      baseCommand: javac
+      stdout: out.txt
+      inputs:
+        - id: "#src"
+          type: File
+          inputBinding:
+            valueFrom:
+              engine: "cwl:JsonPointer"
+              script: "job/src/path"
+      outputs:
+        - id: "#classfile"
+          type: File
+          outputBinding:
+            glob: out.txt
+    inputs:
+      - id: "#compile.src"
+        source: "#inp"
+    outputs:
+      - id: "#compile.classfile"
+    scatter: "#compile.src"
+`
+	upgraded, err := cwl.UpgradeYAML([]byte(src), "v1.0")
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(upgraded)).ToBe(`cwlVersion: v1.0
+class: Workflow
+doc: Compile a java file
+inputs:
+    - id: inp
+      type: File
+    - id: args.py
+      type: File
+outputs:
+    - id: classfile
+      type: File
+      outputSource: compile/classfile
+steps:
+    - id: compile
+      run:
+        class: CommandLineTool
+        baseCommand: javac
+        stdout: out.txt
+        inputs:
+            - id: src
+              type: File
+              inputBinding:
+                valueFrom: $(inputs.src.path)
+        outputs:
+            - id: classfile
+              type: stdout
+      in:
+        - id: src
+          source: inp
+      out:
+        - classfile
+      scatter: src
+`)
+
+	// The upgraded document can be decoded as v1.0.
+	root := cwl.NewCWL()
+	err = root.Decode(strings.NewReader(string(upgraded)))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Steps[0].In[0].Source[0]).ToBe("inp")
+}
+
+func TestUpgradeYAML_javascript(t *testing.T) {
+	src := `
+cwlVersion: draft-3
+class: CommandLineTool
+requirements:
+  - class: ExpressionEngineRequirement
+    id: "#js"
+inputs: []
+outputs: []
+arguments:
+  - valueFrom: {engine: "#js", script: "{return 1;}"}
+  - valueFrom: {engine: "#js", script: "1 + 1"}
+`
+	upgraded, err := cwl.UpgradeYAML([]byte(src), "v1.0")
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(upgraded)).ToBe(`cwlVersion: v1.0
+class: CommandLineTool
+requirements:
+    - class: InlineJavascriptRequirement
+inputs: []
+outputs: []
+arguments:
+    - valueFrom: ${return 1;}
+    - valueFrom: $(1 + 1)
+`)
+}
+
+func TestUpgradeYAML_v10(t *testing.T) {
+	src := `
+cwlVersion: v1.0
+class: CommandLineTool
+baseCommand: ls
+requirements:
+  DockerRequirement:
+    dockerPull: debian:stable
+inputs:
+  dir: Directory
+  bam:
+    type: File
+    secondaryFiles: [.bai, $(self.basename).idx]
+outputs: []
+`
+	upgraded, err := cwl.UpgradeYAML([]byte(src), "v1.2")
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(upgraded)).ToBe(`cwlVersion: v1.2
+class: CommandLineTool
+baseCommand: ls
+requirements:
+    DockerRequirement:
+        dockerPull: debian:stable
+    LoadListingRequirement:
+        loadListing: deep_listing
+    NetworkAccess:
+        networkAccess: true
+inputs:
+    dir: Directory
+    bam:
+        type: File
+        secondaryFiles:
+            - pattern: .bai
+            - $(self.basename).idx
+outputs: []
+`)
+	root := cwl.NewCWL()
+	Expect(t, root.Decode(strings.NewReader(string(upgraded)))).ToBe(nil)
+	Expect(t, root.Requirements[2].NetworkAccess.NetworkAccess).ToBe(true)
+}
+
+func TestUpgradeYAML_v10_NetworkAccess(t *testing.T) {
+	src := "cwlVersion: v1.0\nclass: CommandLineTool\nbaseCommand: curl\nrequirements:\n  - class: NetworkAccess\n    networkAccess: false\ninputs: []\noutputs: []\n"
+	upgraded, err := cwl.UpgradeYAML([]byte(src), "v1.1")
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(upgraded)).ToBe("cwlVersion: v1.1\nclass: CommandLineTool\nbaseCommand: curl\nrequirements:\n    - class: NetworkAccess\n      networkAccess: false\ninputs: []\noutputs: []\n")
+
+	src = "cwlVersion: v1.0\nclass: CommandLineTool\nbaseCommand: curl\nhints:\n  NetworkAccess:\n    networkAccess: false\ninputs: []\noutputs: []\n"
+	upgraded, err = cwl.UpgradeYAML([]byte(src), "v1.2")
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(upgraded)).ToBe("cwlVersion: v1.2\nclass: CommandLineTool\nbaseCommand: curl\nhints:\n    NetworkAccess:\n        networkAccess: false\ninputs: []\noutputs: []\n")
+}
+
+func TestUpgradeYAML_workflow_inputBinding(t *testing.T) {
+	src := "cwlVersion: v1.0\nclass: Workflow\ninputs:\n  text:\n    type: File\n    inputBinding: {loadContents: true}\noutputs: []\nsteps: []\n"
+	upgraded, err := cwl.UpgradeYAML([]byte(src), "v1.1")
+	Expect(t, err).ToBe(nil)
+	Expect(t, string(upgraded)).ToBe("cwlVersion: v1.1\nclass: Workflow\ninputs:\n    text:\n        type: File\n        loadContents: true\noutputs: []\nsteps: []\n")
+}
+
+func TestUpgrade_error(t *testing.T) {
+	_, err := cwl.UpgradeYAML([]byte("cwlVersion: v1.2\nclass: Workflow\n"), "v1.0")
+	Expect(t, err.Error()).ToBe("can't downgrade v1.2 to v1.0")
+	_, err = cwl.UpgradeYAML([]byte("cwlVersion: v1.0\nclass: Workflow\n"), "v2.0")
+	Expect(t, err.Error()).ToBe("unknown cwlVersion: v2.0")
+}
+
+func TestLoader_Version(t *testing.T) {
+	loader, _ := newMemoryLoader(map[string]string{
+		"mem://host/wf.cwl": `
+cwlVersion: v1.2
+class: Workflow
+inputs: {inp: File}
+outputs: []
+steps:
+  step1:
+    run: tool.cwl
+    in: {src: inp}
+    out: []
+`,
+		"mem://host/tool.cwl": `
+cwlVersion: draft-3
+class: CommandLineTool
+description: legacy tool
+inputs:
+  - id: "#src"
+    type: File
+outputs: []
+`,
+	})
+	loader.Version = "v1.2"
+	root, err := loader.Load("mem://host/wf.cwl")
+	Expect(t, err).ToBe(nil)
+	tool := root.Steps[0].Run.Workflow
+	Expect(t, tool.Version).ToBe("v1.2")
+	Expect(t, tool.Doc).ToBe("legacy tool")
+	Expect(t, tool.Inputs[0].ID).ToBe("src")
+}
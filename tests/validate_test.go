@@ -0,0 +1,246 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+func TestValidate(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs:
+  file: File
+  lines: int
+outputs:
+  out:
+    type: File
+    outputSource: cat/output
+  lines:
+    type: int
+    outputSource: wc/nothing
+steps:
+  wc:
+    scatter: nothing
+    in:
+      - {id: file, source: file}
+      - {id: extra, source: file}
+      - {id: file, source: lines}
+    out: [lines, words]
+    run:
+      class: CommandLineTool
+      baseCommand: wc
+      inputs:
+        file: File
+        mode: string
+        opt: string?
+        def:
+          type: int
+          default: 1
+      outputs:
+        lines: int
+  cat:
+    in:
+      file: unknown
+    out: [out]
+    run: cat.cwl
+`))
+	Expect(t, err).ToBe(nil)
+
+	diagnostics := cwl.Validate(root)
+	messages := []string{}
+	for _, d := range diagnostics {
+		messages = append(messages, d.String())
+	}
+	Expect(t, messages).ToBe([]string{
+		`error: outputs.lines: duplicate ID "lines"`,
+		`error: steps.wc.in.file: duplicate ID "file"`,
+		`error: steps.wc.scatter: scatter "nothing" is not an input of the step`,
+		`error: steps.wc.out.words: output "words" is not declared by the process to run`,
+		`error: steps.wc.in.mode: required input "mode" of the process to run is not connected`,
+		`warning: steps.wc.in.extra: input "extra" is not declared by the process to run`,
+		`error: steps.cat.in.file: source "unknown" is not found`,
+		`error: outputs.out: step "cat" has no output "output"`,
+		`error: outputs.lines: step "wc" has no output "nothing"`,
+		`error: steps.wc: scatter requires ScatterFeatureRequirement`,
+		`error: steps.wc.in.file: source lines of type int is not assignable to File`,
+	})
+	Expect(t, diagnostics[5].Severity).ToBe(cwl.SeverityWarning)
+	Expect(t, diagnostics[6].Path).ToBe("steps.cat.in.file")
+}
+
+func TestValidate_cycle(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs:
+  message: string
+outputs: []
+steps:
+  a:
+    in: {message: message, previous: c/out}
+    out: [out]
+    run: echo.cwl
+  b:
+    in: {message: a/out}
+    out: [out]
+    run: echo.cwl
+  c:
+    in: {message: b/out}
+    out: [out]
+    run: echo.cwl
+`))
+	Expect(t, err).ToBe(nil)
+	diagnostics := cwl.Validate(root)
+	Expect(t, len(diagnostics)).ToBe(1)
+	Expect(t, diagnostics[0].String()).ToBe("error: steps.a: steps form a cycle: a -> c -> b -> a")
+}
+
+func TestValidate_nested(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+requirements:
+  SubworkflowFeatureRequirement: {}
+inputs: []
+outputs: []
+steps:
+  sub:
+    in: []
+    out: []
+    run:
+      class: Workflow
+      inputs: []
+      outputs:
+        out:
+          type: string
+          outputSource: inner/out
+      steps:
+        inner:
+          in: []
+          out: [out]
+          run: echo.cwl
+`))
+	Expect(t, err).ToBe(nil)
+	Expect(t, len(cwl.Validate(root))).ToBe(0)
+
+	root.Steps[0].Run.Workflow.Steps[0].Out = nil
+	diagnostics := cwl.Validate(root)
+	Expect(t, len(diagnostics)).ToBe(1)
+	Expect(t, diagnostics[0].String()).ToBe(`error: steps.sub.run.outputs.out: step "inner" has no output "out"`)
+}
+
+func TestValidate_graph(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+$graph:
+  - id: main
+    class: Workflow
+    requirements:
+      SubworkflowFeatureRequirement: {}
+    inputs:
+      n: int
+    outputs: []
+    steps:
+      s:
+        in:
+          n: n
+        out: []
+        run: "#sub"
+      missing:
+        in: []
+        out: []
+        run: "#missing"
+  - id: sub
+    class: Workflow
+    inputs:
+      n: int
+    outputs: []
+    steps:
+      t:
+        in:
+          file: n
+        out: [nothing]
+        run: "#cat"
+  - id: cat
+    class: CommandLineTool
+    baseCommand: cat
+    inputs:
+      file: File
+    outputs: []
+`))
+	Expect(t, err).ToBe(nil)
+
+	messages := []string{}
+	for _, d := range cwl.Validate(root) {
+		messages = append(messages, d.String())
+	}
+	// Problems of "#sub" are reported only once, as a part of "#main".
+	Expect(t, messages).ToBe([]string{
+		`error: $graph[0].steps.s.run.steps.t.out.nothing: output "nothing" is not declared by the process to run`,
+		`error: $graph[0].steps.missing.run: process "#missing" is not found in $graph`,
+		`error: $graph[0].steps.s.run.steps.t.in.file: source n of type int is not assignable to File`,
+	})
+	// Validate links a copy of "$graph", leaving the document as it is.
+	Expect(t, root.Graphs[0].Steps[0].Run.Workflow == nil).ToBe(true)
+	Expect(t, root.Graphs[1].Steps[0].Run.Workflow == nil).ToBe(true)
+}
+
+func TestValidate_undeclared_input(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs:
+  file: File
+outputs: []
+steps:
+  wc:
+    in:
+      file: file
+      lines: file
+    out: [lines]
+    run:
+      class: CommandLineTool
+      baseCommand: wc
+      inputs:
+        file: File
+      outputs:
+        lines: File
+`))
+	Expect(t, err).ToBe(nil)
+	// "lines" is an output of the process to run, but not an input.
+	diagnostics := cwl.Validate(root)
+	Expect(t, len(diagnostics)).ToBe(1)
+	Expect(t, diagnostics[0].String()).ToBe(`warning: steps.wc.in.lines: input "lines" is not declared by the process to run`)
+}
+
+func TestValidate_step_not_found(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(`
+cwlVersion: v1.0
+id: main
+class: Workflow
+inputs:
+  x: string
+outputs:
+  out:
+    type: string
+    outputSource: nostep/x
+  same:
+    type: string
+    outputSource: "#main/x"
+steps: []
+`))
+	Expect(t, err).ToBe(nil)
+	diagnostics := cwl.Validate(root)
+	Expect(t, len(diagnostics)).ToBe(1)
+	Expect(t, diagnostics[0].String()).ToBe(`error: outputs.out: step "nostep" is not found`)
+}
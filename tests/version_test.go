@@ -0,0 +1,124 @@
+package cwlgotest
+
+import (
+	"strings"
+	"testing"
+
+	cwl "github.com/otiai10/cwl.go"
+	. "github.com/otiai10/mint"
+)
+
+const v11tool = `
+cwlVersion: v1.1
+class: CommandLineTool
+baseCommand: cat
+requirements:
+  LoadListingRequirement:
+    loadListing: shallow_listing
+  WorkReuse:
+    enableReuse: $(inputs.reuse)
+  NetworkAccess:
+    networkAccess: true
+  InplaceUpdateRequirement:
+    inplaceUpdate: true
+  ToolTimeLimit:
+    timelimit: 60
+inputs:
+  reuse: boolean
+  in: stdin
+  bam:
+    type: File
+    loadContents: true
+    secondaryFiles:
+      - .bai
+      - pattern: ^.fai
+        required: false
+    inputBinding:
+      position: $(1 + 1)
+outputs:
+  out:
+    type: File
+    secondaryFiles: [{pattern: .idx, required: $(true)}]
+    outputBinding:
+      glob: out.txt
+      loadListing: deep_listing
+`
+
+func TestDecode_v11(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(v11tool))
+	Expect(t, err).ToBe(nil)
+
+	Expect(t, root.Requirements[0].LoadListing).ToBe("shallow_listing")
+	Expect(t, root.Requirements[1].EnableReuse).ToBe(false)
+	Expect(t, root.Requirements[1].EnableReuseExpression).ToBe("$(inputs.reuse)")
+	Expect(t, root.Requirements[2].NetworkAccess.NetworkAccess).ToBe(true)
+	Expect(t, root.Requirements[3].InplaceUpdate).ToBe(true)
+	Expect(t, root.Requirements[4].TimeLimit).ToBe(60)
+
+	Expect(t, root.Inputs[1].Types[0].Type).ToBe("stdin")
+	Expect(t, root.Inputs[2].LoadContents).ToBe(true)
+	Expect(t, root.Inputs[2].Binding.PositionExpression).ToBe("$(1 + 1)")
+	Expect(t, root.Inputs[2].SecondaryFiles[0]).ToBe(cwl.SecondaryFile{Entry: ".bai", Required: true})
+	Expect(t, root.Inputs[2].SecondaryFiles[1]).ToBe(cwl.SecondaryFile{Entry: "^.fai", Required: false})
+	Expect(t, root.Outputs[0].SecondaryFiles[0].RequiredExpression).ToBe("$(true)")
+	Expect(t, root.Outputs[0].Binding.LoadListing).ToBe("deep_listing")
+
+	// enableReuse is true by default.
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader("cwlVersion: v1.1\nclass: CommandLineTool\nhints: []\nrequirements: [{class: WorkReuse}]\ninputs: []\noutputs: []\n"))
+	Expect(t, err).ToBe(nil)
+	Expect(t, root.Requirements[0].EnableReuse).ToBe(true)
+}
+
+func TestDecode_v10_rejects_v11(t *testing.T) {
+	root := cwl.NewCWL()
+	err := root.Decode(strings.NewReader(strings.Replace(v11tool, "v1.1", "v1.0", 1)))
+	Expect(t, err).Not().ToBe(nil)
+	errs, ok := err.(cwl.ParseErrors)
+	Expect(t, ok).ToBe(true)
+	messages := []string{}
+	for _, e := range errs {
+		messages = append(messages, e.Path+": "+e.Message)
+	}
+	Expect(t, messages).ToBe([]string{
+		"inputs.in: type \"stdin\" requires cwlVersion v1.1 or later",
+		"requirements.LoadListingRequirement: LoadListingRequirement requires cwlVersion v1.1 or later",
+		"requirements.WorkReuse: WorkReuse requires cwlVersion v1.1 or later",
+		"requirements.NetworkAccess: NetworkAccess requires cwlVersion v1.1 or later",
+		"requirements.InplaceUpdateRequirement: InplaceUpdateRequirement requires cwlVersion v1.1 or later",
+		"requirements.ToolTimeLimit: ToolTimeLimit requires cwlVersion v1.1 or later",
+		"inputs.bam.loadContents: field \"loadContents\" of InputParameter requires cwlVersion v1.1 or later",
+		"inputs.bam.secondaryFiles[1]: SecondaryFileSchema requires cwlVersion v1.1 or later",
+		"inputs.bam.inputBinding.position: expression in \"position\" requires cwlVersion v1.1 or later",
+		"outputs.out.secondaryFiles[0]: SecondaryFileSchema requires cwlVersion v1.1 or later",
+		"outputs.out.outputBinding.loadListing: field \"loadListing\" of CommandOutputBinding requires cwlVersion v1.1 or later",
+	})
+
+	// Unknown classes in hints are just ignored.
+	root = cwl.NewCWL()
+	err = root.Decode(strings.NewReader("cwlVersion: v1.0\nclass: CommandLineTool\nhints: [{class: NetworkAccess, networkAccess: true}]\ninputs: []\noutputs: []\n"))
+	Expect(t, err).ToBe(nil)
+}
+
+func TestDecode_v11_workflow_inputBinding(t *testing.T) {
+	doc := `
+cwlVersion: v1.1
+class: Workflow
+inputs:
+  text:
+    type: File
+    inputBinding: {loadContents: true}
+outputs: []
+steps: []
+`
+	root := cwl.NewCWL()
+	Expect(t, root.Decode(strings.NewReader(doc))).ToBe(nil)
+	Expect(t, root.Inputs[0].LoadContents).ToBe(true)
+	Expect(t, root.Inputs[0].Binding == nil).ToBe(true)
+
+	root = cwl.NewCWL()
+	err := root.Decode(strings.NewReader(strings.Replace(doc, "{loadContents: true}", "{position: 1}", 1)))
+	Expect(t, err).Not().ToBe(nil)
+	Expect(t, err.Error()).ToBe("Parse error at line 7, column 30: inputs.text.inputBinding.position: inputBinding of workflow inputs can't have \"position\" since v1.1")
+}
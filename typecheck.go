@@ -0,0 +1,319 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LinkError represents a data link whose source is not compatible with the sink.
+type LinkError struct {
+	// Workflow is the path of the nested workflow such as "outer/inner", empty for the root
+	Workflow string
+	// Step is the name of the step, empty if the sink is an output of the workflow
+	Step string
+	// Port is the name of the step input or the workflow output
+	Port    string
+	Source  string
+	Message string
+}
+
+// Error implements error interface.
+func (e *LinkError) Error() string {
+	return fmt.Sprintf("%s: %s", e.path(), e.Message)
+}
+
+// path returns the path of the sink in the document, e.g. "steps.outer.run.steps.step1.in.file1".
+func (e *LinkError) path() string {
+	path := ""
+	if e.Workflow != "" {
+		path = "steps." + strings.Join(strings.Split(e.Workflow, "/"), ".run.steps.") + ".run"
+	}
+	if e.Step == "" {
+		return join(path, "outputs."+e.Port)
+	}
+	return join(path, "steps."+e.Step+".in."+e.Port)
+}
+
+// LinkErrors represents a list of LinkError.
+type LinkErrors []*LinkError
+
+// Error implements error interface.
+func (errs LinkErrors) Error() string {
+	lines := []string{}
+	for _, e := range errs {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CheckLinks checks types of the data links of this workflow and its nested workflows,
+// i.e. "source" of step inputs and "outputSource" of workflow outputs.
+// The type of a source is the type of the workflow input, or the type of the output of the process to run,
+// which is wrapped in array if the step is scattered, and accepts null if the step is conditional.
+// Sources are merged by "linkMerge" and "pickValue", and step inputs with "valueFrom" are not checked.
+// Links from or to steps whose "run" is not loaded are not checked either.
+func (root *Root) CheckLinks() LinkErrors {
+	return root.checkLinks("", root.TypeDefs(), nil)
+}
+
+// checkLinks checks data links of this workflow, which is nested in the path,
+// where stack is the list of the workflows which contain this workflow.
+func (root *Root) checkLinks(path string, defs TypeDefs, stack []*Root) LinkErrors {
+	errs := LinkErrors{}
+	for _, p := range stack {
+		if p == root {
+			return errs
+		}
+	}
+	c := linkChecker{inputs: map[string]*TypeNode{}, outputs: map[string]*TypeNode{}, steps: map[string]bool{}}
+	for _, input := range root.Inputs {
+		c.inputs[ShortName(input.ID)] = resolveTypes(defs, input.Types)
+	}
+	for _, step := range root.Steps {
+		c.steps[ShortName(step.ID)] = true
+		run := step.Run.Workflow
+		if run == nil {
+			continue
+		}
+		for _, out := range step.Out {
+			name := ShortName(out.ID)
+			for _, output := range run.Outputs {
+				if ShortName(output.ID) == name {
+					c.outputs[ShortName(step.ID)+"/"+name] = stepOutputType(step, resolveTypes(defs.with(run), output.Types))
+				}
+			}
+		}
+	}
+	for _, step := range root.Steps {
+		run := step.Run.Workflow
+		if run == nil {
+			continue
+		}
+		name := ShortName(step.ID)
+		for _, in := range step.In {
+			if in.ValueFrom != "" || len(in.Source) == 0 {
+				continue
+			}
+			port := ShortName(in.ID)
+			var sink *TypeNode
+			for _, input := range run.Inputs {
+				if ShortName(input.ID) == port {
+					sink = resolveTypes(defs.with(run), input.Types)
+				}
+			}
+			if sink == nil {
+				continue
+			}
+			scattered := contains(shortNames(step.Scatter), port)
+			if e := c.check(in.Source, in.LinkMerge, in.PickValue, in.Default != nil, scattered, sink); e != nil {
+				e.Workflow, e.Step, e.Port = path, name, port
+				errs = append(errs, e)
+			}
+		}
+		if run.Class == "Workflow" {
+			inner := name
+			if path != "" {
+				inner = path + "/" + name
+			}
+			errs = append(errs, run.checkLinks(inner, defs.with(run), append(stack, root))...)
+		}
+	}
+	for _, output := range root.Outputs {
+		sink := resolveTypes(defs, output.Types)
+		if sink == nil || len(output.Source) == 0 {
+			continue
+		}
+		if e := c.check(output.Source, output.LinkMerge, output.PickValue, false, false, sink); e != nil {
+			e.Workflow, e.Port = path, ShortName(output.ID)
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// linkChecker holds types of the sources in a workflow,
+// keyed by "input" for workflow inputs and "step/output" for step outputs,
+// and the names of the steps.
+type linkChecker struct {
+	inputs  map[string]*TypeNode
+	outputs map[string]*TypeNode
+	steps   map[string]bool
+}
+
+// check returns LinkError if the merged type of the sources is not assignable to the sink,
+// or if it's not an array of the sink type for a scattered input.
+func (c linkChecker) check(sources []string, linkMerge, pickValue string, hasDefault, scattered bool, sink *TypeNode) *LinkError {
+	types := []*TypeNode{}
+	for _, src := range sources {
+		t := c.source(src)
+		if t == nil {
+			return nil
+		}
+		types = append(types, t)
+	}
+	merged := mergeSources(types, linkMerge, pickValue)
+	if hasDefault {
+		merged = nonNull(merged)
+	}
+	source := strings.Join(sources, ", ")
+	if scattered {
+		if merged.Kind != KindArray {
+			return &LinkError{Source: source, Message: fmt.Sprintf("source %s of type %s must be an array to scatter", source, merged)}
+		}
+		if !Assignable(merged.Items, sink) {
+			return &LinkError{Source: source, Message: fmt.Sprintf("items of source %s of type %s are not assignable to %s", source, merged, sink)}
+		}
+		return nil
+	}
+	if Assignable(merged, sink) {
+		return nil
+	}
+	message := fmt.Sprintf("source %s of type %s is not assignable to %s", source, merged, sink)
+	if merged.Kind == KindArray && Assignable(merged.Items, sink) {
+		message += " without scatter"
+	}
+	return &LinkError{Source: source, Message: message}
+}
+
+// source returns the type of the source, or nil if it's unknown.
+// Outputs of steps are unknown if "run" of the steps are not loaded or they are not declared.
+// Otherwise, the source is an input of the workflow, such as "inp1" or "#main/inp1".
+func (c linkChecker) source(src string) *TypeNode {
+	_, fragment := splitFragment(src)
+	if fragment == "" {
+		fragment = src
+	}
+	segments := strings.Split(fragment, "/")
+	port := segments[len(segments)-1]
+	if step := sourceStep(src); c.steps[step] {
+		return c.outputs[step+"/"+port]
+	}
+	return c.inputs[port]
+}
+
+// mergeSources returns the type of the value given to the sink from the sources,
+// by "linkMerge" and "pickValue".
+// Multiple sources are merged by "merge_nested" by default.
+// @see http://www.commonwl.org/v1.0/Workflow.html#WorkflowStepInput
+func mergeSources(types []*TypeNode, linkMerge, pickValue string) *TypeNode {
+	if len(types) > 1 && linkMerge == "" {
+		linkMerge = "merge_nested"
+	}
+	merged := types[0]
+	switch linkMerge {
+	case "merge_nested":
+		merged = &TypeNode{Kind: KindArray, Items: unionOf(types)}
+	case "merge_flattened":
+		items := []*TypeNode{}
+		for _, t := range types {
+			if t.Kind == KindArray {
+				items = append(items, t.Items)
+			} else {
+				items = append(items, t)
+			}
+		}
+		merged = &TypeNode{Kind: KindArray, Items: unionOf(items)}
+	}
+	switch pickValue {
+	case "first_non_null", "the_only_non_null":
+		if merged.Kind == KindArray {
+			return nonNull(merged.Items)
+		}
+		return nonNull(merged)
+	case "all_non_null":
+		if merged.Kind == KindArray {
+			return &TypeNode{Kind: KindArray, Items: nonNull(merged.Items)}
+		}
+	}
+	return merged
+}
+
+// stepOutputType returns the type of the step output from the type of the output of the process,
+// which accepts null if the step is conditional, and is wrapped in array for each scatter
+// in "nested_crossproduct", otherwise once if the step is scattered.
+func stepOutputType(step Step, t *TypeNode) *TypeNode {
+	if t == nil {
+		return nil
+	}
+	if step.Conditional() && !t.Nullable() {
+		t = &TypeNode{Kind: KindUnion, Types: []*TypeNode{{Kind: KindNull}, t}}
+	}
+	if len(step.Scatter) == 0 {
+		return t
+	}
+	depth := 1
+	if step.ScatterMethod == "nested_crossproduct" {
+		depth = len(step.Scatter)
+	}
+	for i := 0; i < depth; i++ {
+		t = &TypeNode{Kind: KindArray, Items: t}
+	}
+	return t
+}
+
+// unionOf returns the union of the types, or the type itself if there's only one kind.
+func unionOf(types []*TypeNode) *TypeNode {
+	members := []*TypeNode{}
+	for _, t := range types {
+		for _, m := range t.members() {
+			if !containsTypes(members, []*TypeNode{m}) {
+				members = append(members, m)
+			}
+		}
+	}
+	if len(members) == 1 {
+		return members[0]
+	}
+	return &TypeNode{Kind: KindUnion, Types: members}
+}
+
+// nonNull returns the type without null.
+func nonNull(t *TypeNode) *TypeNode {
+	if t.Kind != KindUnion {
+		return t
+	}
+	members := []*TypeNode{}
+	for _, m := range t.members() {
+		if m.Kind != KindNull {
+			members = append(members, m)
+		}
+	}
+	if len(members) == 1 {
+		return members[0]
+	}
+	return &TypeNode{Kind: KindUnion, Types: members}
+}
+
+// resolveTypes returns the resolved type, or nil if it's not declared or can't be resolved.
+func resolveTypes(defs TypeDefs, types []Type) *TypeNode {
+	if len(types) == 0 {
+		return nil
+	}
+	t, err := defs.Resolve(ParseTypes(types))
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+// with returns TypeDefs in which the types defined by the process are added,
+// since SchemaDefRequirement of the workflow is inherited by its steps.
+func (defs TypeDefs) with(process *Root) TypeDefs {
+	dest := TypeDefs{}
+	for name, t := range defs {
+		dest[name] = t
+	}
+	for name, t := range process.TypeDefs() {
+		dest[name] = t
+	}
+	return dest
+}
+
+// shortNames returns short names of the IDs.
+func shortNames(ids []string) []string {
+	dest := []string{}
+	for _, id := range ids {
+		dest = append(dest, ShortName(id))
+	}
+	return dest
+}
@@ -10,11 +10,13 @@ import "strings"
 type Type struct {
 	Type    string
 	Label   string
-	Binding *Binding
-	Fields  Fields   // from CommandInputRecordSchema
-	Symbols []string // from CommandInputEnumSchema
-	Items   []Type   // from CommandInputArraySchema
-	Name    string
+	Binding *CommandLineBinding
+	// OutputBinding only appears in output schemas
+	OutputBinding *CommandOutputBinding
+	Fields        Fields   // from CommandInputRecordSchema
+	Symbols       []string // from CommandInputEnumSchema
+	Items         []Type   // from CommandInputArraySchema
+	Name          string
 }
 
 // NewList constructs a list of Type from any interface.
@@ -38,28 +40,35 @@ func (_ Type) New(i interface{}) Type {
 	switch x := i.(type) {
 	case string:
 		dest.Type = x
-	case map[string]interface{}:
-		for key, v := range x {
+	case *Object:
+		x.declare("Schema")
+		for _, key := range x.Keys {
+			v := x.Values[key]
 			switch key {
 			case "type":
-				dest.Type = v.(string)
+				dest.Type = x.String(key)
 			case "items":
 				dest.Items = Type{}.NewList(v)
 			case "inputBinding":
-				dest.Binding = Binding{}.New(v)
+				dest.Binding = CommandLineBinding{}.New(v)
+			case "outputBinding":
+				dest.OutputBinding = CommandOutputBinding{}.New(v)
 			case "fields":
 				dest.Fields = Fields{}.New(v)
 			case "symbols":
-				dest.Symbols = StringArrayable(v)
+				dest.Symbols = x.Strings(key)
 			case "name":
-				dest.Name = v.(string)
+				dest.Name = x.String(key)
 			}
 		}
+	case map[string]interface{}:
+		return Type{}.New(NewObject(x))
 	}
 	return dest
 }
 
-// NeedRequirement ...
+// NeedRequirement returns the name of the type referred with "#" prefix, if any.
+// Use ParseType and TypeDefs to resolve named types.
 func (t Type) NeedRequirement() (string, bool) {
 	if strings.HasPrefix(t.Type, "#") {
 		return strings.TrimPrefix(t.Type, "#"), true
@@ -0,0 +1,434 @@
+package cwl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TypeKind represents the kind of TypeNode.
+type TypeKind string
+
+// Kinds of TypeNode.
+// Primitive types, File, Directory, Any and null are the kinds named after themselves.
+const (
+	KindNull      TypeKind = "null"
+	KindBoolean   TypeKind = "boolean"
+	KindInt       TypeKind = "int"
+	KindLong      TypeKind = "long"
+	KindFloat     TypeKind = "float"
+	KindDouble    TypeKind = "double"
+	KindString    TypeKind = "string"
+	KindFile      TypeKind = "File"
+	KindDirectory TypeKind = "Directory"
+	KindAny       TypeKind = "Any"
+	KindUnion     TypeKind = "union"
+	KindArray     TypeKind = "array"
+	KindRecord    TypeKind = "record"
+	KindEnum      TypeKind = "enum"
+	// KindNamed is a reference to a type defined by SchemaDefRequirement,
+	// which is not resolved yet.
+	KindNamed TypeKind = "named"
+)
+
+// namedKinds are the kinds which can be written as a plain type name.
+var namedKinds = map[string]TypeKind{
+	"null":      KindNull,
+	"boolean":   KindBoolean,
+	"int":       KindInt,
+	"long":      KindLong,
+	"float":     KindFloat,
+	"double":    KindDouble,
+	"string":    KindString,
+	"File":      KindFile,
+	"Directory": KindDirectory,
+	"Any":       KindAny,
+	// Shortcuts of File in CommandLineTool
+	"stdin":  KindFile,
+	"stdout": KindFile,
+	"stderr": KindFile,
+}
+
+// TypeNode is the canonical form of a CWL type,
+// in which "?" and "[]" shorthands are expanded to union and array.
+// @see http://www.commonwl.org/v1.0/SchemaSalad.html#Type_DSL
+type TypeNode struct {
+	Kind TypeKind
+	// Name is the name of record and enum, or the reference of named type
+	Name string
+	// Items only appears if Kind is array
+	Items *TypeNode
+	// Types only appears if Kind is union
+	Types []*TypeNode
+	// Fields only appears if Kind is record
+	Fields []TypeField
+	// Symbols only appears if Kind is enum
+	Symbols []string
+}
+
+// TypeField is a field of record TypeNode.
+type TypeField struct {
+	Name string
+	Type *TypeNode
+}
+
+// ParseTypes converts the list of Type to TypeNode,
+// which is a union if it has more than one type.
+// It returns nil if no type is declared, which is neither Any nor assignable to any type.
+func ParseTypes(types []Type) *TypeNode {
+	switch len(types) {
+	case 0:
+		return nil
+	case 1:
+		return ParseType(types[0])
+	}
+	dest := &TypeNode{Kind: KindUnion}
+	for _, t := range types {
+		dest.Types = append(dest.Types, ParseType(t))
+	}
+	return dest
+}
+
+// ParseType converts Type to TypeNode, expanding "?" and "[]" shorthands.
+func ParseType(t Type) *TypeNode {
+	switch t.Type {
+	case "array":
+		return &TypeNode{Kind: KindArray, Name: t.Name, Items: ParseTypes(t.Items)}
+	case "record":
+		dest := &TypeNode{Kind: KindRecord, Name: t.Name}
+		for _, f := range t.Fields {
+			dest.Fields = append(dest.Fields, TypeField{Name: ShortName(f.Name), Type: ParseTypes(f.Types)})
+		}
+		return dest
+	case "enum":
+		return &TypeNode{Kind: KindEnum, Name: t.Name, Symbols: t.Symbols}
+	}
+	return parseTypeName(t.Type)
+}
+
+// parseTypeName converts the name of type such as "File[]?" to TypeNode.
+func parseTypeName(name string) *TypeNode {
+	switch {
+	case strings.HasSuffix(name, "?"):
+		return &TypeNode{Kind: KindUnion, Types: []*TypeNode{
+			{Kind: KindNull},
+			parseTypeName(strings.TrimSuffix(name, "?")),
+		}}
+	case strings.HasSuffix(name, "[]"):
+		return &TypeNode{Kind: KindArray, Items: parseTypeName(strings.TrimSuffix(name, "[]"))}
+	}
+	if kind, ok := namedKinds[name]; ok {
+		return &TypeNode{Kind: kind}
+	}
+	return &TypeNode{Kind: KindNamed, Name: name}
+}
+
+// String returns the type in the shorthand notation, e.g. "File[]?".
+func (t *TypeNode) String() string {
+	if t == nil {
+		return "undeclared"
+	}
+	switch t.Kind {
+	case KindNamed:
+		return t.Name
+	case KindRecord, KindEnum:
+		if t.Name != "" {
+			return t.Name
+		}
+		return string(t.Kind)
+	case KindArray:
+		s := t.Items.String()
+		if t.Items != nil && t.Items.Kind == KindUnion {
+			s = "(" + s + ")"
+		}
+		return s + "[]"
+	case KindUnion:
+		members := []string{}
+		nullable := false
+		for _, m := range t.Types {
+			if m.Kind == KindNull {
+				nullable = true
+				continue
+			}
+			members = append(members, m.String())
+		}
+		if nullable && len(members) == 1 {
+			return members[0] + "?"
+		}
+		if nullable {
+			members = append([]string{"null"}, members...)
+		}
+		return strings.Join(members, " | ")
+	}
+	return string(t.Kind)
+}
+
+// Nullable returns true if null is a valid value of this type.
+func (t *TypeNode) Nullable() bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind {
+	case KindNull:
+		return true
+	case KindUnion:
+		for _, m := range t.Types {
+			if m.Nullable() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// members returns the members of the union flattened, or this type itself.
+func (t *TypeNode) members() []*TypeNode {
+	if t.Kind != KindUnion {
+		return []*TypeNode{t}
+	}
+	dest := []*TypeNode{}
+	for _, m := range t.Types {
+		dest = append(dest, m.members()...)
+	}
+	return dest
+}
+
+// Equal returns true if both types are the same,
+// regarding unions as sets of the members.
+func (t *TypeNode) Equal(u *TypeNode) bool {
+	if t == nil || u == nil {
+		return t == u
+	}
+	if t.Kind == KindUnion || u.Kind == KindUnion {
+		tm, um := t.members(), u.members()
+		return containsTypes(tm, um) && containsTypes(um, tm)
+	}
+	if t.Kind != u.Kind {
+		return false
+	}
+	switch t.Kind {
+	case KindNamed:
+		return ShortName(t.Name) == ShortName(u.Name)
+	case KindArray:
+		return t.Items.Equal(u.Items)
+	case KindRecord:
+		if len(t.Fields) != len(u.Fields) {
+			return false
+		}
+		for _, f := range t.Fields {
+			g := u.field(f.Name)
+			if g == nil || !f.Type.Equal(g.Type) {
+				return false
+			}
+		}
+	case KindEnum:
+		ts, us := enumSymbols(t), enumSymbols(u)
+		return strings.Join(ts, ",") == strings.Join(us, ",")
+	}
+	return true
+}
+
+// containsTypes returns true if every type of the subset is equal to any of the set.
+func containsTypes(set, subset []*TypeNode) bool {
+	for _, s := range subset {
+		found := false
+		for _, t := range set {
+			if s.Equal(t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// field returns the field of the record by its name, or nil if not found.
+func (t *TypeNode) field(name string) *TypeField {
+	for i := range t.Fields {
+		if t.Fields[i].Name == name {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
+
+// enumSymbols returns the sorted short names of the symbols.
+func enumSymbols(t *TypeNode) []string {
+	dest := []string{}
+	for _, s := range t.Symbols {
+		dest = append(dest, ShortName(s))
+	}
+	sort.Strings(dest)
+	return dest
+}
+
+// Assignable returns true if any value of the type src can be given to the type dest,
+// allowing numeric promotions such as int to long.
+// Any accepts every value but null, and a value of Any is regarded as assignable to anything.
+// Undeclared types (nil) are not assignable at all.
+func Assignable(src, dest *TypeNode) bool {
+	if src == nil || dest == nil {
+		return false
+	}
+	if src.Kind == KindUnion {
+		for _, m := range src.Types {
+			if !Assignable(m, dest) {
+				return false
+			}
+		}
+		return true
+	}
+	if dest.Kind == KindUnion {
+		for _, m := range dest.Types {
+			if Assignable(src, m) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case src.Kind == KindAny:
+		return true
+	case dest.Kind == KindAny:
+		return src.Kind != KindNull
+	}
+	switch dest.Kind {
+	case KindLong:
+		return src.Kind == KindInt || src.Kind == KindLong
+	case KindFloat:
+		return src.Kind == KindInt || src.Kind == KindLong || src.Kind == KindFloat
+	case KindDouble:
+		return src.Kind == KindInt || src.Kind == KindLong || src.Kind == KindFloat || src.Kind == KindDouble
+	case KindArray:
+		return src.Kind == KindArray && Assignable(src.Items, dest.Items)
+	case KindRecord:
+		if src.Kind != KindRecord {
+			return false
+		}
+		for _, f := range dest.Fields {
+			g := src.field(f.Name)
+			if g == nil {
+				if !f.Type.Nullable() {
+					return false
+				}
+			} else if !Assignable(g.Type, f.Type) {
+				return false
+			}
+		}
+		return true
+	case KindEnum:
+		if src.Kind != KindEnum {
+			return false
+		}
+		symbols := enumSymbols(dest)
+		for _, s := range enumSymbols(src) {
+			if !contains(symbols, s) {
+				return false
+			}
+		}
+		return true
+	}
+	return src.Equal(dest)
+}
+
+// TypeDefs is the named types defined by SchemaDefRequirement,
+// keyed by their short names such as "Stage" for "#Stage".
+type TypeDefs map[string]*TypeNode
+
+// TypeDefs collects the named types defined by SchemaDefRequirement of this process,
+// including named records and enums nested in them.
+// Types given by "$import" are available only if the document is loaded by Loader.
+func (root *Root) TypeDefs() TypeDefs {
+	defs := TypeDefs{}
+	for _, r := range root.Requirements {
+		for _, t := range r.SchemaDefRequirement.Types {
+			defs.add(ParseType(t))
+		}
+	}
+	return defs
+}
+
+// add registers the type and named types nested in it.
+func (defs TypeDefs) add(t *TypeNode) {
+	if t == nil {
+		return
+	}
+	switch t.Kind {
+	case KindRecord, KindEnum:
+		if t.Name != "" {
+			defs[ShortName(t.Name)] = t
+		}
+		for _, f := range t.Fields {
+			defs.add(f.Type)
+		}
+	case KindArray:
+		defs.add(t.Items)
+	case KindUnion:
+		for _, m := range t.Types {
+			defs.add(m)
+		}
+	}
+}
+
+// Resolve returns the type in which all the named references are replaced by the defined types.
+// It returns an error if any of them is not defined, or if any type is not declared (nil).
+func (defs TypeDefs) Resolve(t *TypeNode) (*TypeNode, error) {
+	return defs.resolve(t, nil)
+}
+
+// resolve resolves the type, detecting references to the types being resolved.
+func (defs TypeDefs) resolve(t *TypeNode, stack []string) (*TypeNode, error) {
+	if t == nil {
+		return nil, fmt.Errorf("type is not declared")
+	}
+	dest := *t
+	switch t.Kind {
+	case KindNamed:
+		name := ShortName(t.Name)
+		def, ok := defs[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s is not defined", t.Name)
+		}
+		if contains(stack, name) {
+			return nil, fmt.Errorf("type %s refers to itself", t.Name)
+		}
+		return defs.resolve(def, append(append([]string{}, stack...), name))
+	case KindArray:
+		items, err := defs.resolve(t.Items, stack)
+		if err != nil {
+			return nil, err
+		}
+		dest.Items = items
+	case KindUnion:
+		dest.Types = []*TypeNode{}
+		for _, m := range t.Types {
+			r, err := defs.resolve(m, stack)
+			if err != nil {
+				return nil, err
+			}
+			dest.Types = append(dest.Types, r)
+		}
+	case KindRecord:
+		if t.Name != "" {
+			stack = append(append([]string{}, stack...), ShortName(t.Name))
+		}
+		dest.Fields = []TypeField{}
+		for _, f := range t.Fields {
+			r, err := defs.resolve(f.Type, stack)
+			if err != nil {
+				return nil, err
+			}
+			dest.Fields = append(dest.Fields, TypeField{Name: f.Name, Type: r})
+		}
+	}
+	return &dest, nil
+}
+
+// ResolveType converts the list of Type to TypeNode,
+// resolving named types by SchemaDefRequirement of this process.
+func (root *Root) ResolveType(types []Type) (*TypeNode, error) {
+	return root.TypeDefs().Resolve(ParseTypes(types))
+}
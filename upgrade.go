@@ -0,0 +1,474 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// upgraders rewrite a process of the previous version to the version.
+var upgraders = map[string]func(*Object){
+	Version10: upgradeDraft3,
+	Version11: upgradeV10,
+	Version12: func(*Object) {},
+}
+
+// upgrade rewrites the decoded document to the version, as cwl-upgrader does,
+// so that a workflow can mix tools written in different versions.
+// It upgrades the document step by step, e.g. draft-3 to v1.0, v1.0 to v1.1, and v1.1 to v1.2,
+// including the processes in "$graph" and embedded in "run" of steps.
+func upgrade(root *Object, version string) error {
+	target := versionIndex(version)
+	if target < 0 {
+		return fmt.Errorf("unknown cwlVersion: %s", version)
+	}
+	current, _ := root.Values["cwlVersion"].(string)
+	if current == "" {
+		return fmt.Errorf("cwlVersion is not specified")
+	}
+	if versionIndex(current) < 0 {
+		return fmt.Errorf("unknown cwlVersion: %s", current)
+	}
+	if versionIndex(current) > target {
+		return fmt.Errorf("can't downgrade %s to %s", current, version)
+	}
+	for n := versionIndex(current) + 1; n <= target; n++ {
+		eachProcess(root, upgraders[versions[n]])
+	}
+	eachProcess(root, func(p *Object) {
+		if _, ok := p.Get("cwlVersion"); ok {
+			p.Set("cwlVersion", version)
+		}
+	})
+	return nil
+}
+
+// UpgradeYAML upgrades the YAML (or JSON) document to the version, as cwl-upgrader does,
+// so that a workflow can mix tools written in different versions, and emits the upgraded YAML.
+// Set Loader.Version to upgrade documents while loading them.
+// A decoded Root can't be upgraded, because it doesn't keep what the upgrade rewrites,
+// such as draft-3 expressions and "#" references, so the document is upgraded before decoding.
+func UpgradeYAML(src []byte, version string) ([]byte, error) {
+	doc, err := decodeYAML(src)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := doc.(*Object)
+	if !ok {
+		return nil, fmt.Errorf("document must be a mapping but got %s", kindOf(doc))
+	}
+	if err := upgrade(root, version); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(root)
+}
+
+// MarshalYAML implements yaml.Marshaler, keeping the order of keys.
+func (obj *Object) MarshalYAML() (interface{}, error) {
+	return toNode(obj)
+}
+
+// toNode converts the decoded tree to yaml.Node.
+func toNode(i interface{}) (*yaml.Node, error) {
+	switch x := i.(type) {
+	case *Object:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range x.Keys {
+			value, err := toNode(x.Values[key])
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+		}
+		return node, nil
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, v := range x {
+			value, err := toNode(v)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node, nil
+	case float64:
+		if x == float64(int64(x)) {
+			i = int64(x)
+		}
+	}
+	node := new(yaml.Node)
+	return node, node.Encode(i)
+}
+
+// eachProcess calls f with the process, and the processes in "$graph" and in "run" of steps.
+func eachProcess(p *Object, f func(*Object)) {
+	f(p)
+	for _, g := range elements(p.Values["$graph"]) {
+		eachProcess(g, f)
+	}
+	for _, step := range elements(p.Values["steps"]) {
+		if run, ok := step.Values["run"].(*Object); ok {
+			eachProcess(run, f)
+		}
+	}
+}
+
+// elements returns the objects of list-form or map-form field, such as "inputs" and "steps".
+func elements(i interface{}) []*Object {
+	dest := []*Object{}
+	switch x := i.(type) {
+	case []interface{}:
+		for _, v := range x {
+			if obj, ok := v.(*Object); ok {
+				dest = append(dest, obj)
+			}
+		}
+	case *Object:
+		for _, key := range x.Keys {
+			if obj, ok := x.Values[key].(*Object); ok {
+				dest = append(dest, obj)
+			}
+		}
+	}
+	return dest
+}
+
+// rename renames the key of the object, keeping its position in the order.
+func rename(obj *Object, from, to string) {
+	v, ok := obj.Get(from)
+	if !ok {
+		return
+	}
+	if _, exists := obj.Get(to); exists {
+		return
+	}
+	for n, key := range obj.Keys {
+		if key == from {
+			obj.Keys[n] = to
+		}
+	}
+	delete(obj.Values, from)
+	obj.Values[to] = v
+}
+
+// upgradeDraft3 rewrites draft-3 process to v1.0:
+// "#" of identifiers and references are removed, "engine" expressions are
+// rewritten to parameter references or JavaScript expressions,
+// step "inputs" and "outputs" are renamed to "in" and "out",
+// and outputs globbing "stdout" file are rewritten to "stdout" type.
+func upgradeDraft3(p *Object) {
+	javascript := upgradeExpressions(p)
+	rename(p, "description", "doc")
+	steps := map[string]bool{}
+	for _, step := range elements(p.Values["steps"]) {
+		if id, ok := step.Values["id"].(string); ok {
+			steps[strings.TrimPrefix(id, "#")] = true
+		}
+	}
+	for _, field := range []string{"inputs", "outputs"} {
+		for _, param := range elements(p.Values[field]) {
+			rename(param, "description", "doc")
+			trimID(param, "")
+			if field == "outputs" && p.Values["class"] == "Workflow" {
+				rename(param, "source", "outputSource")
+				upgradeSources(param, "outputSource", steps)
+			}
+		}
+	}
+	for _, step := range elements(p.Values["steps"]) {
+		rename(step, "description", "doc")
+		trimID(step, "")
+		rename(step, "inputs", "in")
+		rename(step, "outputs", "out")
+		prefix, _ := step.Values["id"].(string)
+		for _, in := range elements(step.Values["in"]) {
+			trimID(in, prefix)
+			upgradeSources(in, "source", steps)
+		}
+		if outs, ok := step.Values["out"].([]interface{}); ok {
+			for n, out := range outs {
+				if obj, ok := out.(*Object); ok {
+					trimID(obj, prefix)
+					outs[n] = obj.Values["id"]
+				}
+			}
+		}
+		if scatter, ok := step.Values["scatter"]; ok {
+			step.Set("scatter", trimIDs(scatter, prefix))
+		}
+	}
+	upgradeRequirements(p, javascript)
+	upgradeStdout(p)
+}
+
+// upgradeExpressions rewrites draft-3 expressions {engine: ..., script: ...} in the tree,
+// and returns true if any of them is JavaScript.
+func upgradeExpressions(i interface{}) bool {
+	javascript := false
+	switch x := i.(type) {
+	case *Object:
+		for _, key := range x.Keys {
+			if expr, ok := x.Values[key].(*Object); ok && expr.Len() == 2 {
+				engine, ok1 := expr.Values["engine"].(string)
+				script, ok2 := expr.Values["script"].(string)
+				if ok1 && ok2 {
+					js := engine != "cwl:JsonPointer"
+					x.Values[key] = upgradeExpression(script, js)
+					javascript = javascript || js
+					continue
+				}
+			}
+			if key == "run" || key == "$graph" {
+				// Embedded processes are upgraded by eachProcess.
+				continue
+			}
+			javascript = upgradeExpressions(x.Values[key]) || javascript
+		}
+	case []interface{}:
+		for _, v := range x {
+			javascript = upgradeExpressions(v) || javascript
+		}
+	}
+	return javascript
+}
+
+// upgradeExpression rewrites a draft-3 script to v1.0 expression.
+func upgradeExpression(script string, javascript bool) string {
+	script = strings.TrimSpace(script)
+	if javascript {
+		if strings.HasPrefix(script, "{") {
+			return "$" + script
+		}
+		return "$(" + script + ")"
+	}
+	// JSON pointer such as "job/input/path" or "context"
+	segments := strings.Split(strings.Trim(script, "/"), "/")
+	switch segments[0] {
+	case "job":
+		segments[0] = "inputs"
+	case "context":
+		segments[0] = "self"
+	}
+	return "$(" + strings.Join(segments, ".") + ")"
+}
+
+// trimID removes "#" and the prefix of the step from "id", e.g. "#step1.input" to "input".
+func trimID(obj *Object, step string) {
+	if id, ok := obj.Values["id"].(string); ok {
+		obj.Set("id", trimIDs(id, step))
+	}
+}
+
+// trimIDs removes "#" and the prefix of the step from references.
+func trimIDs(i interface{}, step string) interface{} {
+	switch x := i.(type) {
+	case string:
+		x = strings.TrimPrefix(x, "#")
+		if step != "" {
+			x = strings.TrimPrefix(x, step+".")
+		}
+		return x
+	case []interface{}:
+		dest := []interface{}{}
+		for _, v := range x {
+			dest = append(dest, trimIDs(v, step))
+		}
+		return dest
+	}
+	return i
+}
+
+// upgradeSources rewrites draft-3 sources, e.g. "#step1.output" to "step1/output".
+func upgradeSources(obj *Object, key string, steps map[string]bool) {
+	upgrade := func(src string) string {
+		src = strings.TrimPrefix(src, "#")
+		if n := strings.Index(src, "."); n >= 0 && steps[src[:n]] {
+			return src[:n] + "/" + src[n+1:]
+		}
+		return src
+	}
+	switch x := obj.Values[key].(type) {
+	case string:
+		obj.Set(key, upgrade(x))
+	case []interface{}:
+		for n, v := range x {
+			if s, ok := v.(string); ok {
+				x[n] = upgrade(s)
+			}
+		}
+	}
+}
+
+// upgradeRequirements removes ExpressionEngineRequirement, adds InlineJavascriptRequirement
+// if JavaScript is used, and rewrites CreateFileRequirement to InitialWorkDirRequirement.
+func upgradeRequirements(p *Object, javascript bool) {
+	if javascript {
+		addRequirement(p, "InlineJavascriptRequirement", nil)
+	}
+	for _, field := range []string{"requirements", "hints"} {
+		list, ok := p.Values[field].([]interface{})
+		if !ok {
+			continue
+		}
+		dest := []interface{}{}
+		for _, v := range list {
+			r, ok := v.(*Object)
+			if !ok {
+				dest = append(dest, v)
+				continue
+			}
+			switch r.Values["class"] {
+			case "ExpressionEngineRequirement":
+				continue
+			case "CreateFileRequirement":
+				r.Set("class", "InitialWorkDirRequirement")
+				rename(r, "fileDef", "listing")
+				for _, def := range elements(r.Values["listing"]) {
+					rename(def, "filename", "entryname")
+					rename(def, "fileContent", "entry")
+				}
+			}
+			dest = append(dest, r)
+		}
+		if len(dest) == 0 {
+			p.Delete(field)
+		} else {
+			p.Set(field, dest)
+		}
+	}
+}
+
+// upgradeStdout rewrites outputs of File type globbing the "stdout" file to "stdout" type.
+func upgradeStdout(p *Object) {
+	stdout, ok := p.Values["stdout"].(string)
+	if !ok || isExpression(stdout) {
+		return
+	}
+	for _, output := range elements(p.Values["outputs"]) {
+		binding, ok := output.Values["outputBinding"].(*Object)
+		if !ok || output.Values["type"] != "File" || binding.Values["glob"] != stdout || binding.Len() != 1 {
+			continue
+		}
+		output.Set("type", "stdout")
+		output.Delete("outputBinding")
+	}
+}
+
+// upgradeV10 rewrites v1.0 process to v1.1:
+// "secondaryFiles" patterns are rewritten to SecondaryFileSchema,
+// "inputBinding" of workflow inputs is replaced by "loadContents",
+// and implicit behaviors of v1.0, deep listing of directories and network access of tools,
+// are required explicitly.
+func upgradeV10(p *Object) {
+	for _, field := range []string{"inputs", "outputs"} {
+		for _, param := range elements(p.Values[field]) {
+			if files, ok := param.Values["secondaryFiles"]; ok {
+				param.Set("secondaryFiles", upgradeSecondaryFiles(files))
+			}
+			binding, ok := param.Values["inputBinding"].(*Object)
+			if !ok || p.Values["class"] != "Workflow" {
+				continue
+			}
+			if b, ok := binding.Values["loadContents"].(bool); ok && b {
+				param.Set("loadContents", true)
+			}
+			param.Delete("inputBinding")
+		}
+	}
+	switch p.Values["class"] {
+	case "CommandLineTool":
+		if usesDirectory(p.Values["inputs"]) {
+			addRequirement(p, "LoadListingRequirement", &Object{Keys: []string{"loadListing"}, Values: map[string]interface{}{"loadListing": "deep_listing"}})
+		}
+		// v1.0 tools were allowed to access network, which must be required explicitly since v1.1.
+		// cwl-upgrader also adds it to every tool unless it's already given in requirements or hints.
+		if !declares(p.Values["hints"], "NetworkAccess") {
+			addRequirement(p, "NetworkAccess", &Object{Keys: []string{"networkAccess"}, Values: map[string]interface{}{"networkAccess": true}})
+		}
+	case "ExpressionTool":
+		if usesDirectory(p.Values["inputs"]) {
+			addRequirement(p, "LoadListingRequirement", &Object{Keys: []string{"loadListing"}, Values: map[string]interface{}{"loadListing": "deep_listing"}})
+		}
+	}
+}
+
+// upgradeSecondaryFiles rewrites patterns to SecondaryFileSchema, leaving expressions.
+func upgradeSecondaryFiles(i interface{}) interface{} {
+	switch x := i.(type) {
+	case string:
+		if isExpression(x) {
+			return x
+		}
+		return &Object{Keys: []string{"pattern"}, Values: map[string]interface{}{"pattern": x}}
+	case []interface{}:
+		dest := []interface{}{}
+		for _, v := range x {
+			dest = append(dest, upgradeSecondaryFiles(v))
+		}
+		return dest
+	}
+	return i
+}
+
+// usesDirectory returns true if Directory type appears in the tree.
+func usesDirectory(i interface{}) bool {
+	switch x := i.(type) {
+	case string:
+		return strings.TrimRight(x, "?[]") == "Directory"
+	case *Object:
+		for _, key := range x.Keys {
+			if key != "default" && usesDirectory(x.Values[key]) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, v := range x {
+			if usesDirectory(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// declares returns true if list-form or map-form "requirements" or "hints" has the class.
+func declares(i interface{}, class string) bool {
+	switch x := i.(type) {
+	case *Object:
+		_, ok := x.Get(class)
+		return ok
+	case []interface{}:
+		for _, r := range elements(x) {
+			if r.Values["class"] == class {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addRequirement adds the requirement of the class unless it's already required,
+// in the same form as existing "requirements".
+func addRequirement(p *Object, class string, fields *Object) {
+	if fields == nil {
+		fields = &Object{Values: map[string]interface{}{}}
+	}
+	if declares(p.Values["requirements"], class) {
+		return
+	}
+	switch x := p.Values["requirements"].(type) {
+	case *Object:
+		x.Set(class, fields)
+	case []interface{}:
+		r := &Object{Keys: []string{"class"}, Values: map[string]interface{}{"class": class}}
+		for _, key := range fields.Keys {
+			r.Set(key, fields.Values[key])
+		}
+		p.Set("requirements", append(x, r))
+	default:
+		requirements := &Object{Values: map[string]interface{}{}}
+		requirements.Set(class, fields)
+		p.Set("requirements", requirements)
+	}
+}
@@ -0,0 +1,318 @@
+package cwl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severities of Diagnostic.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic represents a problem of a document found by Validate.
+type Diagnostic struct {
+	// Severity is either SeverityError or SeverityWarning
+	Severity string
+	// Path is the path of the problem in the document, e.g. "steps.step1.in.file1"
+	Path    string
+	Message string
+}
+
+// String returns the diagnostic in the form of "error: steps.step1.in.file1: message".
+func (d Diagnostic) String() string {
+	if d.Path == "" {
+		return fmt.Sprintf("%s: %s", d.Severity, d.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Path, d.Message)
+}
+
+// Validate checks the structure of the document, and its nested workflows:
+//   - IDs of inputs, outputs and steps are unique
+//   - "source", "outputSource" and "scatter" refer to existing parameters
+//   - "out" of steps are declared by the processes to run
+//   - inputs of steps are declared by the processes to run, or it's a warning
+//   - required inputs of the processes to run are connected or defaulted
+//   - steps don't form a cycle
+//   - types of the data links are compatible, as CheckLinks does
+//   - feature requirements are declared if the features are used, or it's an error,
+//     and they are used if they are declared, or it's a warning
+//
+// For a document with "$graph", "run" of steps referring to "#xxx" are linked as Entry does,
+// but in a copy of "$graph" so that root is not modified,
+// and the processes run by others are checked as parts of them.
+// Processes to run which are not loaded are not checked.
+func Validate(root *Root) []Diagnostic {
+	v := &validator{diagnostics: []Diagnostic{}}
+	if len(root.Graphs) == 0 {
+		v.process(root, "", nil)
+		v.features(root, "", nil, nil)
+		v.links(root, "")
+		return v.diagnostics
+	}
+	v.graph = true
+	graphs := root.Graphs.clone()
+	// Processes not found in "$graph" are reported by each step which runs them.
+	graphs.link()
+	// Processes run by others in "$graph" are validated as parts of them, inheriting their requirements,
+	// unless they are only run by themselves recursively.
+	run := map[*Root]bool{}
+	for _, g := range graphs {
+		for _, step := range g.Steps {
+			reach(step.Run.Workflow, run)
+		}
+	}
+	validated := map[*Root]bool{}
+	for _, standalone := range []bool{true, false} {
+		for i, g := range graphs {
+			if validated[g] || (standalone && run[g]) {
+				continue
+			}
+			path := fmt.Sprintf("$graph[%d]", i)
+			v.process(g, path, nil)
+			v.features(g, path, nil, nil)
+			v.links(g, path)
+			reach(g, validated)
+		}
+	}
+	return v.diagnostics
+}
+
+// reach marks the process and the processes run by its steps recursively.
+func reach(p *Root, reached map[*Root]bool) {
+	if p == nil || reached[p] {
+		return
+	}
+	reached[p] = true
+	for _, step := range p.Steps {
+		reach(step.Run.Workflow, reached)
+	}
+}
+
+// validator collects diagnostics.
+type validator struct {
+	diagnostics []Diagnostic
+	// graph is true if the document has "$graph", where "run" can refer to the processes in it
+	graph bool
+}
+
+// fail records an error at the path.
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{Severity: SeverityError, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// warn records a warning at the path.
+func (v *validator) warn(path, format string, args ...interface{}) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{Severity: SeverityWarning, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// links records LinkErrors of the workflow as errors.
+func (v *validator) links(root *Root, path string) {
+	if root.Class != "Workflow" {
+		return
+	}
+	for _, e := range root.CheckLinks() {
+		v.fail(join(path, e.path()), "%s", e.Message)
+	}
+}
+
+// process validates the process and its nested workflows,
+// where stack is the list of the workflows which contain this process.
+func (v *validator) process(root *Root, path string, stack []*Root) {
+	for _, p := range stack {
+		if p == root {
+			v.fail(path, "process runs itself recursively")
+			return
+		}
+	}
+	ids := map[string]bool{}
+	unique := func(id, path string) {
+		name := ShortName(id)
+		if ids[name] {
+			v.fail(path, "duplicate ID \"%s\"", name)
+		}
+		ids[name] = true
+	}
+	for _, input := range root.Inputs {
+		unique(input.ID, join(path, "inputs."+ShortName(input.ID)))
+	}
+	for _, output := range root.Outputs {
+		unique(output.ID, join(path, "outputs."+ShortName(output.ID)))
+	}
+	for _, step := range root.Steps {
+		unique(step.ID, join(path, "steps."+ShortName(step.ID)))
+	}
+	if root.Class != "Workflow" {
+		return
+	}
+	for _, step := range root.Steps {
+		v.step(root, step, join(path, "steps."+ShortName(step.ID)))
+		if step.Run.Workflow != nil {
+			v.process(step.Run.Workflow, join(path, "steps."+ShortName(step.ID)+".run"), append(stack, root))
+		}
+	}
+	for _, output := range root.Outputs {
+		for _, src := range output.Source {
+			if msg := v.source(root, src); msg != "" {
+				v.fail(join(path, "outputs."+ShortName(output.ID)), "%s", msg)
+			}
+		}
+	}
+	if cycle := findCycle(root.Steps); cycle != nil {
+		v.fail(join(path, "steps."+cycle[0]), "steps form a cycle: %s", strings.Join(cycle, " -> "))
+	}
+}
+
+// step validates references of the step.
+func (v *validator) step(root *Root, step Step, path string) {
+	ins := map[string]bool{}
+	for _, in := range step.In {
+		port := ShortName(in.ID)
+		if ins[port] {
+			v.fail(join(path, "in."+port), "duplicate ID \"%s\"", port)
+		}
+		ins[port] = true
+		for _, src := range in.Source {
+			if msg := v.source(root, src); msg != "" {
+				v.fail(join(path, "in."+port), "%s", msg)
+			}
+		}
+	}
+	outs := map[string]bool{}
+	for _, out := range step.Out {
+		name := ShortName(out.ID)
+		if outs[name] {
+			v.fail(join(path, "out."+name), "duplicate ID \"%s\"", name)
+		}
+		outs[name] = true
+	}
+	for _, s := range step.Scatter {
+		if !ins[ShortName(s)] {
+			v.fail(join(path, "scatter"), "scatter \"%s\" is not an input of the step", s)
+		}
+	}
+	run := step.Run.Workflow
+	if run == nil {
+		if v.graph && strings.HasPrefix(step.Run.Value, "#") {
+			v.fail(join(path, "run"), "process \"%s\" is not found in $graph", step.Run.Value)
+		}
+		return
+	}
+	outputs := map[string]bool{}
+	for _, output := range run.Outputs {
+		outputs[ShortName(output.ID)] = true
+	}
+	for _, out := range step.Out {
+		if name := ShortName(out.ID); !outputs[name] {
+			v.fail(join(path, "out."+name), "output \"%s\" is not declared by the process to run", name)
+		}
+	}
+	connected := map[string]bool{}
+	for _, in := range step.In {
+		connected[ShortName(in.ID)] = len(in.Source) != 0 || in.Default != nil || in.ValueFrom != ""
+	}
+	inputs := map[string]bool{}
+	for _, input := range run.Inputs {
+		name := ShortName(input.ID)
+		inputs[name] = true
+		if connected[name] || input.Default != nil || len(input.Types) == 0 || ParseTypes(input.Types).Nullable() {
+			continue
+		}
+		v.fail(join(path, "in."+name), "required input \"%s\" of the process to run is not connected", name)
+	}
+	for _, in := range step.In {
+		if name := ShortName(in.ID); !inputs[name] {
+			v.warn(join(path, "in."+name), "input \"%s\" is not declared by the process to run", name)
+		}
+	}
+}
+
+// source returns the message if the source can't be resolved in the workflow, otherwise "".
+func (v *validator) source(root *Root, src string) string {
+	_, fragment := splitFragment(src)
+	if fragment == "" {
+		fragment = src
+	}
+	segments := strings.Split(fragment, "/")
+	port := segments[len(segments)-1]
+	if name := sourceStep(src); name != "" {
+		for _, step := range root.Steps {
+			if ShortName(step.ID) != name {
+				continue
+			}
+			for _, out := range step.Out {
+				if ShortName(out.ID) == port {
+					return ""
+				}
+			}
+			return fmt.Sprintf("step \"%s\" has no output \"%s\"", name, port)
+		}
+		// Otherwise, it must be an input scoped by the workflow itself, e.g. "#main/inp1".
+		if root.ID == "" || ShortName(root.ID) != name {
+			return fmt.Sprintf("step \"%s\" is not found", name)
+		}
+	}
+	for _, input := range root.Inputs {
+		if ShortName(input.ID) == port {
+			return ""
+		}
+	}
+	return fmt.Sprintf("source \"%s\" is not found", src)
+}
+
+// findCycle returns the names of steps which form a cycle by their sources,
+// such as ["a", "b", "a"], or nil if there's no cycle.
+func findCycle(steps Steps) []string {
+	deps := map[string][]string{}
+	names := []string{}
+	for _, step := range steps {
+		name := ShortName(step.ID)
+		names = append(names, name)
+		for _, in := range step.In {
+			for _, src := range in.Source {
+				if s := sourceStep(src); s != "" {
+					deps[name] = append(deps[name], s)
+				}
+			}
+		}
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := map[string]int{}
+	stack := []string{}
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch states[name] {
+		case visiting:
+			for n, s := range stack {
+				if s == name {
+					return append(append([]string{}, stack[n:]...), name)
+				}
+			}
+		case visited:
+			return nil
+		}
+		states[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range deps[name] {
+			if !contains(names, dep) {
+				continue
+			}
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		states[name] = visited
+		return nil
+	}
+	for _, name := range names {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package cwl
+
+import (
+	"fmt"
+	"math"
+)
+
+// Versions of CWL specification, in the order of release.
+const (
+	VersionDraft3 = "draft-3"
+	Version10     = "v1.0"
+	Version11     = "v1.1"
+	Version12     = "v1.2"
+)
+
+// versions lists supported cwlVersion, oldest first.
+var versions = []string{VersionDraft3, Version10, Version11, Version12}
+
+// sinceRecords are records added after v1.0, with the version they appear.
+var sinceRecords = map[string]string{
+	"SecondaryFileSchema":      Version11,
+	"LoadListingRequirement":   Version11,
+	"WorkReuse":                Version11,
+	"NetworkAccess":            Version11,
+	"InplaceUpdateRequirement": Version11,
+	"ToolTimeLimit":            Version11,
+	"Operation":                Version12,
+}
+
+// sinceFields are fields added to records after v1.0, with the version they appear.
+var sinceFields = map[string]map[string]string{
+	"InputParameter":       {"loadContents": Version11, "loadListing": Version11},
+	"CommandOutputBinding": {"loadListing": Version11},
+	"RecordField":          {"secondaryFiles": Version11, "streamable": Version11, "format": Version11, "loadContents": Version11, "loadListing": Version11},
+	"WorkflowStepInput":    {"label": Version11, "loadContents": Version11, "loadListing": Version11, "pickValue": Version12},
+	"OutputParameter":      {"pickValue": Version12},
+	"WorkflowStep":         {"when": Version12},
+}
+
+// processes are classes which can be the root of a document.
+var processes = []string{"CommandLineTool", "Workflow", "ExpressionTool", "Operation"}
+
+// versionIndex returns the order of the version, or -1 if it's unknown.
+func versionIndex(version string) int {
+	for n, v := range versions {
+		if v == version {
+			return n
+		}
+	}
+	return -1
+}
+
+// before returns true if the version is known and older than since.
+func before(version, since string) bool {
+	n := versionIndex(version)
+	return n >= 0 && n < versionIndex(since)
+}
+
+// atLeast returns true if the version is known and not older than since.
+func atLeast(version, since string) bool {
+	n := versionIndex(version)
+	return n >= 0 && n >= versionIndex(since)
+}
+
+// versionScope is the context of checkVersion.
+type versionScope struct {
+	// version is cwlVersion of the process
+	version string
+	// class is the class of the process
+	class string
+	// hint is true under "hints", where unknown classes are just ignored
+	hint bool
+}
+
+// checkVersion walks the tree and records ParseErrors for constructs
+// which are not available in cwlVersion of the document, such as
+// v1.1 requirements in v1.0 document.
+func checkVersion(i interface{}, sc versionScope) {
+	switch x := i.(type) {
+	case *Object:
+		if contains(processes, x.record) {
+			if v, ok := x.Values["cwlVersion"].(string); ok {
+				sc.version = v
+			}
+			sc.class, sc.hint = x.record, false
+			if inputs, ok := x.Values["inputs"].(*Object); ok && before(sc.version, Version11) {
+				for _, key := range inputs.Keys {
+					if inputs.Values[key] == "stdin" {
+						inputs.failKey(key, requires("type \"stdin\"", Version11))
+					}
+				}
+			}
+		}
+		if since, ok := sinceRecords[x.record]; ok && !sc.hint && before(sc.version, since) {
+			x.failWith(requires(x.record, since))
+		}
+		for _, key := range x.Keys {
+			if since := sinceFields[x.record][key]; since != "" && before(sc.version, since) {
+				x.failKey(key, requires(fmt.Sprintf("field \"%s\" of %s", key, x.record), since))
+			}
+		}
+		checkValues(x, sc)
+		for _, key := range x.Keys {
+			inner := sc
+			switch key {
+			case "hints":
+				inner.hint = true
+			case "requirements":
+				inner.hint = false
+			}
+			checkVersion(x.Values[key], inner)
+		}
+	case []interface{}:
+		for _, v := range x {
+			checkVersion(v, sc)
+		}
+	}
+}
+
+// checkValues checks values of the object which are only valid in later versions.
+func checkValues(x *Object, sc versionScope) {
+	switch x.record {
+	case "CommandLineBinding":
+		if s, ok := x.Values["position"].(string); ok && isExpression(s) && before(sc.version, Version11) {
+			x.failKey("position", requires("expression in \"position\"", Version11))
+		}
+	case "ResourceRequirement":
+		for _, key := range x.Keys {
+			if f, ok := x.Values[key].(float64); ok && f != math.Trunc(f) && before(sc.version, Version12) {
+				x.failKey(key, requires(fmt.Sprintf("fractional value in \"%s\"", key), Version12))
+			}
+		}
+	case "InputParameter":
+		if x.Values["type"] == "stdin" && before(sc.version, Version11) {
+			x.failKey("type", requires("type \"stdin\"", Version11))
+		}
+		// Since v1.1, "inputBinding" of workflow inputs only has "loadContents".
+		if binding, ok := x.Values["inputBinding"].(*Object); ok && sc.class == "Workflow" && atLeast(sc.version, Version11) {
+			for _, key := range binding.Keys {
+				if key != "loadContents" {
+					binding.failKey(key, fmt.Sprintf("inputBinding of workflow inputs can't have \"%s\" since %s", key, Version11))
+				}
+			}
+		}
+	}
+}
+
+// requires describes the construct requires the version.
+func requires(construct, version string) string {
+	return fmt.Sprintf("%s requires cwlVersion %s or later", construct, version)
+}
+
+// applyVersion adjusts the decoded process to its cwlVersion,
+// recursing into "$graph" and embedded processes.
+// Since v1.1, "inputBinding" of workflow inputs is dropped
+// and its "loadContents" is moved to the input.
+func (root *Root) applyVersion(version string) {
+	if root.Version != "" {
+		version = root.Version
+	}
+	if root.Class == "Workflow" && atLeast(version, Version11) {
+		for i := range root.Inputs {
+			if binding := root.Inputs[i].Binding; binding != nil {
+				root.Inputs[i].LoadContents = root.Inputs[i].LoadContents || binding.LoadContents
+				root.Inputs[i].Binding = nil
+			}
+		}
+	}
+	for _, g := range root.Graphs {
+		g.applyVersion(version)
+	}
+	for _, step := range root.Steps {
+		if step.Run.Workflow != nil {
+			step.Run.Workflow.applyVersion(version)
+		}
+	}
+}